@@ -0,0 +1,262 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"context"
+	"sync"
+)
+
+// An OverflowPolicy determines what an asynchronous deck does with a new message when its
+// queue is full.
+type OverflowPolicy int
+
+const (
+	// Block waits until there is room in the queue.
+	Block OverflowPolicy = iota
+	// DropNewest discards the new message.
+	DropNewest
+	// DropOldest discards the oldest queued message to make room for the new one.
+	DropOldest
+	// DropBelowLevel discards new messages below AsyncOptions.MinLevel, and waits for room
+	// in the queue for all others.
+	DropBelowLevel
+)
+
+// AsyncOptions configures asynchronous delivery of messages. See Deck.SetAsync.
+type AsyncOptions struct {
+	// QueueSize is the number of messages which may be waiting for delivery. Defaults to 1024.
+	QueueSize int
+	// Workers is the number of goroutines delivering messages. Defaults to 1. With more than
+	// one worker, messages may reach the backends out of order.
+	Workers int
+	// Overflow determines what happens to new messages when the queue is full.
+	Overflow OverflowPolicy
+	// MinLevel is the lowest level which is never dropped under the DropBelowLevel policy.
+	MinLevel Level
+}
+
+// SetAsync configures asynchronous delivery for the default deck.
+func SetAsync(opts *AsyncOptions) {
+	defaultDeck.SetAsync(opts)
+}
+
+// SetAsync switches the deck to asynchronous delivery.
+//
+// By default, Go writes a message to every backend before returning. In asynchronous mode, Go
+// instead places the message on a bounded queue, and worker goroutines write it to the
+// backends. The message's time and caller are captured when Go is called, as they are for
+// synchronous delivery, so backends report them correctly.
+//
+// FATAL messages are always delivered synchronously, after the queue has been flushed. Errors
+// from backends are reported to the error handler only, so GoErr returns nil for queued
+// messages.
+//
+// Calling SetAsync again replaces the queue, and a nil opts returns the deck to synchronous
+// delivery. Messages already queued are delivered before SetAsync returns. The error handler
+// may call SetAsync, Flush or Close while a worker is delivering a message; they then return
+// without waiting for the rest of the delivery of that message.
+func (d *Deck) SetAsync(opts *AsyncOptions) {
+	d = d.root()
+	var q *asyncQueue
+	if opts != nil {
+		q = newAsyncQueue(*opts)
+	}
+	if old := d.async.Swap(q); old != nil {
+		old.stop()
+	}
+}
+
+// Dropped returns the number of messages dropped by the deck's overflow policy.
+func (d *Deck) Dropped() uint64 {
+	return d.root().dropped.Load()
+}
+
+type asyncQueue struct {
+	opts    AsyncOptions
+	queue   chan *record
+	workers sync.WaitGroup
+	ids     sync.Map // map[uint64]struct{}, the goroutine IDs of running workers
+
+	// senders holds a read lock while sending to queue, so that stop can close it safely.
+	senders sync.RWMutex
+	stopped bool
+
+	mu      sync.Mutex
+	pending int           // messages queued or being delivered
+	idle    chan struct{} // closed whenever pending is zero
+}
+
+func newAsyncQueue(opts AsyncOptions) *asyncQueue {
+	if opts.QueueSize < 1 {
+		opts.QueueSize = 1024
+	}
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	q := &asyncQueue{
+		opts:  opts,
+		queue: make(chan *record, opts.QueueSize),
+		idle:  make(chan struct{}),
+	}
+	close(q.idle)
+	q.workers.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+func (q *asyncQueue) work() {
+	id := goroutineID()
+	q.ids.Store(id, struct{}{})
+	defer q.exit(id)
+	for l := range q.queue {
+		q.deliver(l)
+	}
+}
+
+func (q *asyncQueue) deliver(l *record) {
+	l.deliver()
+	l.release()
+	q.done()
+}
+
+// exit marks the worker with goroutine ID id as stopped, unless it already has been.
+func (q *asyncQueue) exit(id uint64) {
+	if _, ok := q.ids.LoadAndDelete(id); ok {
+		q.workers.Done()
+	}
+}
+
+// worker returns the goroutine ID of the caller and reports whether it is one of q's workers,
+// as it is when the error handler runs for a queued message.
+func (q *asyncQueue) worker() (uint64, bool) {
+	id := goroutineID()
+	_, ok := q.ids.Load(id)
+	return id, ok
+}
+
+// drain delivers the messages waiting in the queue on the calling goroutine.
+func (q *asyncQueue) drain() {
+	for {
+		select {
+		case l, ok := <-q.queue:
+			if !ok {
+				return
+			}
+			q.deliver(l)
+		default:
+			return
+		}
+	}
+}
+
+// enqueue queues l for delivery, applying the overflow policy if the queue is full. It returns
+// false if the queue has been stopped, in which case the caller should deliver l itself.
+func (q *asyncQueue) enqueue(l *record) bool {
+	q.senders.RLock()
+	defer q.senders.RUnlock()
+	if q.stopped {
+		return false
+	}
+
+	q.mu.Lock()
+	if q.pending == 0 {
+		q.idle = make(chan struct{})
+	}
+	q.pending++
+	q.mu.Unlock()
+
+	policy := q.opts.Overflow
+	if policy == DropBelowLevel {
+		policy = Block
+		if l.level < q.opts.MinLevel {
+			policy = DropNewest
+		}
+	}
+	switch policy {
+	case DropNewest:
+		select {
+		case q.queue <- l:
+		default:
+			q.drop(l)
+		}
+	case DropOldest:
+		for {
+			select {
+			case q.queue <- l:
+				return true
+			default:
+			}
+			select {
+			case old := <-q.queue:
+				q.drop(old)
+			default:
+			}
+		}
+	default:
+		q.queue <- l
+	}
+	return true
+}
+
+func (q *asyncQueue) drop(l *record) {
+	l.deck.dropped.Add(1)
+	l.release()
+	q.done()
+}
+
+func (q *asyncQueue) done() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending--
+	if q.pending == 0 {
+		close(q.idle)
+	}
+}
+
+func (q *asyncQueue) flush(ctx context.Context) error {
+	// A worker can't wait for the message it is delivering, so it delivers the rest itself.
+	if _, ok := q.worker(); ok {
+		q.drain()
+		return nil
+	}
+	q.mu.Lock()
+	idle := q.idle
+	q.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop delivers all queued messages and stops the workers. When called by a worker, it
+// delivers the queued messages itself and doesn't wait for that worker to stop.
+func (q *asyncQueue) stop() {
+	q.senders.Lock()
+	if !q.stopped {
+		q.stopped = true
+		close(q.queue)
+	}
+	q.senders.Unlock()
+	if id, ok := q.worker(); ok {
+		q.drain()
+		q.exit(id)
+	}
+	q.workers.Wait()
+}
@@ -0,0 +1,315 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import "time"
+
+// An AttribStore stores unique attributes associated with a given Log.
+//
+// Attributes are stored under typed keys created with NewKey. Backends can interrogate the
+// store for values with Key.Get, and use the values for their own purposes.
+//
+// An AttribStore is owned by a single Log, which serializes access to it. Attributes are
+// kept in a small inline array in the order they were first stored, so typical messages
+// don't allocate.
+type AttribStore struct {
+	n      int
+	inline [8]attrib
+	more   []attrib
+	fields []KeyValue
+
+	// The values captured for every message are kept apart from other attributes, so that
+	// storing them doesn't allocate. See TimeKey, CallerKey and GoroutineKey.
+	time      time.Time
+	hasTime   bool
+	caller    *Caller
+	goroutine uint64
+
+	// Sampling attributes are private to the deck, so they are kept out of Range too.
+	sampler    sampler
+	hasSampler bool
+	sampleKey  string
+}
+
+type attrib struct {
+	key, value any
+}
+
+// at returns the i'th attribute in the store.
+func (s *AttribStore) at(i int) *attrib {
+	if i < len(s.inline) {
+		return &s.inline[i]
+	}
+	return &s.more[i-len(s.inline)]
+}
+
+// index returns the position of key in the store, or -1.
+func (s *AttribStore) index(key any) int {
+	for i := 0; i < s.n; i++ {
+		if s.at(i).key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *AttribStore) load(key any) (any, bool) {
+	if i := s.index(key); i >= 0 {
+		return s.at(i).value, true
+	}
+	return nil, false
+}
+
+func (s *AttribStore) store(key, value any) {
+	if i := s.index(key); i >= 0 {
+		s.at(i).value = value
+		return
+	}
+	if s.n < len(s.inline) {
+		s.inline[s.n] = attrib{key, value}
+	} else {
+		s.more = append(s.more, attrib{key, value})
+	}
+	s.n++
+}
+
+func (s *AttribStore) delete(key any) {
+	i := s.index(key)
+	if i < 0 {
+		return
+	}
+	for ; i < s.n-1; i++ {
+		*s.at(i) = *s.at(i + 1)
+	}
+	s.n--
+	*s.at(s.n) = attrib{}
+	if s.n >= len(s.inline) {
+		s.more = s.more[:s.n-len(s.inline)]
+	}
+}
+
+// maxRetained limits the storage kept by reset, so one unusual message can't pin a large
+// allocation.
+const maxRetained = 64
+
+// reset empties the store, keeping its storage for reuse.
+func (s *AttribStore) reset() {
+	clear(s.inline[:min(s.n, len(s.inline))])
+	clear(s.more)
+	clear(s.fields)
+	s.more, s.fields = s.more[:0], s.fields[:0]
+	if cap(s.more) > maxRetained {
+		s.more = nil
+	}
+	if cap(s.fields) > maxRetained {
+		s.fields = nil
+	}
+	s.n = 0
+	s.time, s.hasTime, s.caller, s.goroutine = time.Time{}, false, nil, 0
+	s.sampler, s.hasSampler, s.sampleKey = sampler{}, false, ""
+}
+
+// A Caller is the location of the code which logged a message. PC is a program counter as
+// returned by runtime.Callers, suitable for use in a slog.Record.
+type Caller struct {
+	PC       uintptr
+	File     string
+	Line     int
+	Function string
+}
+
+// A Key identifies a value of type T in an AttribStore.
+//
+// Every Key returned by NewKey is distinct, even from other keys with the same name, so
+// packages defining their own attributes cannot collide with one another. Values are checked
+// against T when they are read, so a mismatched value is reported as missing rather than
+// causing a panic.
+type Key[T any] struct {
+	name string
+	slot *slot[T] // nil for keys returned by NewKey
+}
+
+// A slot stores the values of a built in key in a dedicated field of the AttribStore.
+type slot[T any] struct {
+	get func(s *AttribStore) (T, bool)
+	set func(s *AttribStore, v T)
+	del func(s *AttribStore)
+}
+
+// NewKey returns a new Key for values of type T. The name is used by String, and by the
+// string-keyed compatibility methods of AttribStore.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// String returns the name of the key.
+func (k *Key[T]) String() string {
+	return k.name
+}
+
+func (k *Key[T]) keyName() string {
+	return k.name
+}
+
+// namedKey is implemented by every Key, regardless of its type.
+type namedKey interface {
+	keyName() string
+}
+
+// slotKey is implemented by every Key with a slot, regardless of its type.
+type slotKey interface {
+	namedKey
+	loadSlot(s *AttribStore) (any, bool)
+	deleteSlot(s *AttribStore)
+}
+
+func (k *Key[T]) loadSlot(s *AttribStore) (any, bool) {
+	if v, ok := k.slot.get(s); ok {
+		return v, true
+	}
+	return nil, false
+}
+
+func (k *Key[T]) deleteSlot(s *AttribStore) {
+	k.slot.del(s)
+}
+
+// Get returns the value stored under k. If no value is stored under k itself, a value of type T
+// stored with the string-keyed Store method under the name of k is returned.
+func (k *Key[T]) Get(s *AttribStore) (T, bool) {
+	if k.slot != nil {
+		if t, ok := k.slot.get(s); ok {
+			return t, true
+		}
+	}
+	v, ok := s.load(k)
+	if !ok {
+		v, ok = s.load(k.name)
+	}
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	t, ok := v.(T)
+	return t, ok
+}
+
+// Set stores v under k.
+func (k *Key[T]) Set(s *AttribStore, v T) {
+	if k.slot != nil {
+		k.slot.set(s, v)
+		return
+	}
+	s.store(k, v)
+}
+
+// Load returns the value stored under key. If key is a string with no value stored under it,
+// the value of a typed key with the same name is returned instead.
+//
+// Deprecated: Load exists for compatibility with string-keyed attributes. Use Key.Get.
+func (s *AttribStore) Load(key any) (any, bool) {
+	if v, ok := s.load(key); ok {
+		return v, true
+	}
+	name, ok := key.(string)
+	if !ok {
+		return nil, false
+	}
+	for i := 0; i < s.n; i++ {
+		if nk, ok := s.at(i).key.(namedKey); ok && nk.keyName() == name {
+			return s.at(i).value, true
+		}
+	}
+	for _, k := range slotKeys {
+		if k.keyName() == name {
+			return k.loadSlot(s)
+		}
+	}
+	return nil, false
+}
+
+// Store stores value under key. A string key is visible to typed keys of the same name, as long
+// as the value has the type of the typed key.
+//
+// Deprecated: Store exists for compatibility with string-keyed attributes. Use Key.Set.
+func (s *AttribStore) Store(key, value any) {
+	s.store(key, value)
+}
+
+// Delete deletes the value stored under key.
+func (s *AttribStore) Delete(key any) {
+	if k, ok := key.(slotKey); ok {
+		k.deleteSlot(s)
+		return
+	}
+	s.delete(key)
+}
+
+// Range calls f for each attribute in the store, in the order they were first stored, until
+// f returns false. The time, caller and goroutine captured for the message follow the other
+// attributes. Typed keys are passed to f by name. Fields are not included; use Fields.
+func (s *AttribStore) Range(f func(key, value any) bool) {
+	for i := 0; i < s.n; i++ {
+		k, v := s.at(i).key, s.at(i).value
+		if nk, ok := k.(namedKey); ok {
+			k = nk.keyName()
+		}
+		if !f(k, v) {
+			return
+		}
+	}
+	for _, k := range slotKeys {
+		if v, ok := k.loadSlot(s); ok && !f(k.keyName(), v) {
+			return
+		}
+	}
+}
+
+var (
+	// DepthKey holds the Depth attribute.
+	DepthKey = NewKey[int]("Depth")
+	// VerbosityKey holds the verbosity attribute set with V.
+	VerbosityKey = NewKey[int]("Verbosity")
+	// TimeKey holds the time of the event recorded by a message. The deck sets it when the
+	// message is committed, unless it was already set, such as for records received from
+	// log/slog.
+	TimeKey = &Key[time.Time]{name: "Time", slot: &slot[time.Time]{
+		get: func(s *AttribStore) (time.Time, bool) { return s.time, s.hasTime },
+		set: func(s *AttribStore, t time.Time) { s.time, s.hasTime = t, true },
+		del: func(s *AttribStore) { s.time, s.hasTime = time.Time{}, false },
+	}}
+	// CallerKey holds the location of the code which logged a message. The deck sets it when
+	// the message is committed, honoring the Depth attribute.
+	CallerKey = &Key[Caller]{name: "Caller", slot: &slot[Caller]{
+		get: func(s *AttribStore) (Caller, bool) {
+			if s.caller == nil {
+				return Caller{}, false
+			}
+			return *s.caller, true
+		},
+		set: func(s *AttribStore, c Caller) { s.caller = &c },
+		del: func(s *AttribStore) { s.caller = nil },
+	}}
+	// GoroutineKey holds the ID of the goroutine which logged a message. The deck only sets
+	// it when enabled with SetGoroutineIDs.
+	GoroutineKey = &Key[uint64]{name: "Goroutine", slot: &slot[uint64]{
+		get: func(s *AttribStore) (uint64, bool) { return s.goroutine, s.goroutine != 0 },
+		set: func(s *AttribStore, id uint64) { s.goroutine = id },
+		del: func(s *AttribStore) { s.goroutine = 0 },
+	}}
+)
+
+// slotKeys lists the keys stored in slots, in the order Range visits them.
+var slotKeys = []slotKey{TimeKey, CallerKey, GoroutineKey}
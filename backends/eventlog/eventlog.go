@@ -19,9 +19,12 @@ import (
 	"github.com/google/deck"
 )
 
+// eventIDKey holds the EventID attribute.
+var eventIDKey = deck.NewKey[uint32]("EventID")
+
 // EventID is an attribute that appends Event Log Event IDs to log messages.
 func EventID(id uint32) func(*deck.AttribStore) {
 	return func(a *deck.AttribStore) {
-		a.Store("EventID", id)
+		eventIDKey.Set(a, id)
 	}
 }
@@ -18,7 +18,6 @@
 package eventlog
 
 import (
-	"errors"
 	"strings"
 
 	"github.com/google/deck"
@@ -103,13 +102,15 @@ func (e *EventLog) New(lvl deck.Level, msg string) deck.Composer {
 	return &message{parent: e, level: lvl, message: msg, eventID: 1}
 }
 
-// Compose composes the Event prior to writing.
+// Compose composes the Event prior to writing. Any fields attached to the message are
+// appended to it as key=value pairs.
 func (m *message) Compose(s *deck.AttribStore) error {
-	id, ok := s.Load("EventID")
-	if !ok {
-		return errors.New("invalid EventID")
+	if fields := deck.Fields(s); len(fields) > 0 {
+		m.message = strings.TrimSuffix(m.message, "\n") + " " + deck.FormatFields(fields)
+	}
+	if id, ok := eventIDKey.Get(s); ok {
+		m.eventID = id
 	}
-	m.eventID = id.(uint32)
 	return nil
 }
 
@@ -117,15 +118,14 @@ func (m *message) Compose(s *deck.AttribStore) error {
 func (m *message) Write() error {
 	switch m.level {
 	case deck.INFO:
-		m.parent.handle.Info(m.eventID, m.message)
+		return m.parent.handle.Info(m.eventID, m.message)
 	case deck.WARNING:
-		m.parent.handle.Warning(m.eventID, m.message)
+		return m.parent.handle.Warning(m.eventID, m.message)
 	case deck.ERROR:
-		m.parent.handle.Error(m.eventID, m.message)
+		return m.parent.handle.Error(m.eventID, m.message)
 	case deck.FATAL:
-		m.parent.handle.Error(m.eventID, m.message)
+		return m.parent.handle.Error(m.eventID, m.message)
 	default:
-		m.parent.handle.Info(m.eventID, m.message)
+		return m.parent.handle.Info(m.eventID, m.message)
 	}
-	return nil
 }
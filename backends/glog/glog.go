@@ -34,12 +34,24 @@
 //
 //	deck.InfoA("a message with verbosity").With(glog.V(3)).Go()
 //
-// The glog backend will also attempt to honor deck's Depth() attribute, allowing the
-// caller to modify the stack frames output by glog messages.
+// The glog backend reports the caller captured by the deck (see deck.CallerKey), which
+// honors deck's Depth() attribute and remains correct when the deck delivers messages
+// asynchronously. The caller is passed to glog through the writers of
+// glog.NewStandardLogger, which accept a "file:line: " prefix.
+//
+// glog offers no way to pass a time in, so it stamps each message with the time it is
+// written rather than deck.TimeKey. Under deck's SetAsync, that is the time a worker
+// delivers the message, which may be later than the time it was logged.
+//
+// FATAL messages are written at glog's ERROR severity. Terminating the program is left
+// to deck, so that every attached backend receives the message before exit.
 package glog
 
 import (
-	"errors"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	log "github.com/golang/glog"
 	"github.com/google/deck"
@@ -58,16 +70,31 @@ func Init(opts *Options) *GLog {
 			DebugLevel: 1,
 		}
 	}
-	return &GLog{opts: opts}
+	return &GLog{
+		opts:    opts,
+		info:    log.NewStandardLogger("INFO").Writer(),
+		warning: log.NewStandardLogger("WARNING").Writer(),
+		error:   log.NewStandardLogger("ERROR").Writer(),
+	}
 }
 
 // GLog is a log deck backend that passes logs through to the glog package.
 type GLog struct {
 	opts *Options
+
+	// Writers which log "file:line: message" lines at each glog severity.
+	info, warning, error io.Writer
 }
 
-// Close closes the glog backend.
+// Close closes the glog backend, flushing any pending log I/O.
 func (g *GLog) Close() error {
+	log.Flush()
+	return nil
+}
+
+// Flush flushes all pending glog I/O.
+func (g *GLog) Flush() error {
+	log.Flush()
 	return nil
 }
 
@@ -76,7 +103,7 @@ type message struct {
 	level     deck.Level
 	glogLevel log.Level
 	message   string
-	depth     int
+	caller    deck.Caller
 }
 
 // New creates a new GLog message.
@@ -84,46 +111,52 @@ func (g *GLog) New(lvl deck.Level, msg string) deck.Composer {
 	return &message{parent: g, level: lvl, message: msg}
 }
 
-// Adding an offset of 3 excludes the frames in glog.go and deck.go, so the user's
-// code locations should be rendered by default.
-const depthOffset = 3
-
 // Write flushes the stored message to glog.
 func (m *message) Write() error {
-	if m.glogLevel != 0 {
-		log.V(m.glogLevel).InfoDepth(m.depth+depthOffset-1, m.message)
-		return nil
+	w := m.parent.info
+	switch {
+	case m.glogLevel != 0:
+		if !log.V(m.glogLevel) {
+			return nil
+		}
+	case m.level == deck.DEBUG:
+		if !log.V(m.parent.opts.DebugLevel) {
+			return nil
+		}
+	case m.level == deck.WARNING:
+		w = m.parent.warning
+	case m.level == deck.ERROR, m.level == deck.FATAL:
+		w = m.parent.error
 	}
-	switch m.level {
-	case deck.DEBUG:
-		log.V(m.parent.opts.DebugLevel).InfoDepth(m.depth+depthOffset, m.message)
-	case deck.INFO:
-		log.InfoDepth(m.depth+depthOffset, m.message)
-	case deck.WARNING:
-		log.WarningDepth(m.depth+depthOffset, m.message)
-	case deck.ERROR:
-		log.ErrorDepth(m.depth+depthOffset, m.message)
-	case deck.FATAL:
-		log.FatalDepth(m.depth+depthOffset, m.message)
-	default:
-		log.InfoDepth(m.depth+depthOffset, m.message)
+
+	file, line := "???", 1
+	if m.caller.File != "" {
+		file, line = filepath.Base(m.caller.File), m.caller.Line
 	}
-	return nil
+	buf := make([]byte, 0, len(file)+len(m.message)+16)
+	buf = append(buf, file...)
+	buf = append(buf, ':')
+	buf = strconv.AppendInt(buf, int64(line), 10)
+	buf = append(buf, ": "...)
+	buf = append(buf, m.message...)
+	_, err := w.Write(buf)
+	return err
 }
 
-// Compose composes the message prior to writing.
+// Compose composes the message prior to writing. Any fields attached to the message are
+// appended to it as key=value pairs, followed on later lines by any details from
+// deck.AppendDetails, such as a stack captured with deck.Stack.
 func (m *message) Compose(s *deck.AttribStore) error {
-	id, ok := s.Load("GlogV")
-	if !ok {
-		return errors.New("invalid GlogV")
+	if fields := deck.Fields(s); len(fields) > 0 {
+		m.message = strings.TrimSuffix(m.message, "\n") + " " + deck.FormatFields(fields)
 	}
-	m.glogLevel = id.(log.Level)
-
-	dep, ok := s.Load("Depth")
-	if !ok {
-		return errors.New("invalid Depth")
+	if d := deck.AppendDetails(nil, s); len(d) > 0 {
+		m.message = strings.TrimSuffix(m.message, "\n") + "\n" + string(d)
+	}
+	if lvl, ok := vKey.Get(s); ok {
+		m.glogLevel = lvl
 	}
-	m.depth = dep.(int)
+	m.caller, _ = deck.CallerKey.Get(s)
 	return nil
 }
 
@@ -133,6 +166,9 @@ func (m *message) Compose(s *deck.AttribStore) error {
 // glog.V() only modifies the behavior of glog.
 func V(level log.Level) func(*deck.AttribStore) {
 	return func(a *deck.AttribStore) {
-		a.Store("GlogV", level)
+		vKey.Set(a, level)
 	}
 }
+
+// vKey holds the glog V attribute.
+var vKey = deck.NewKey[log.Level]("GlogV")
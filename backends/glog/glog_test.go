@@ -0,0 +1,84 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glog
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	log "github.com/golang/glog"
+	"github.com/google/deck"
+)
+
+// line returns the line number of its caller.
+func line() int {
+	_, _, l, _ := runtime.Caller(1)
+	return l
+}
+
+func TestCaller(t *testing.T) {
+	dir := t.TempDir()
+	for k, v := range map[string]string{"log_dir": dir, "v": "1"} {
+		if err := flag.Set(k, v); err != nil {
+			t.Fatalf("flag.Set(%q, %q): %v", k, v, err)
+		}
+	}
+	d := deck.New()
+	d.Add(Init(nil))
+	deck.Add(Init(nil))
+	defer deck.Close()
+
+	tests := []struct {
+		desc string
+		f    func() int
+	}{
+		{"Info", func() int { d.Info("Info"); return line() }},
+		{"Debug", func() int { d.Debug("Debug"); return line() }},
+		{"Warning", func() int { d.Warning("Warning"); return line() }},
+		{"Error", func() int { d.Error("Error"); return line() }},
+		{"InfoA Go", func() int { d.InfoA("InfoA Go").Go(); return line() }},
+		{"InfoA GoErr", func() int { d.InfoA("InfoA GoErr").GoErr(); return line() }},
+		{"glog V", func() int { d.InfoA("glog V").With(V(1)).Go(); return line() }},
+		{"deck.Info", func() int { deck.Info("deck.Info"); return line() }},
+	}
+	var want []string
+	for _, tt := range tests {
+		want = append(want, fmt.Sprintf("glog_test.go:%d] %s", tt.f(), tt.desc))
+	}
+	d.SetAsync(&deck.AsyncOptions{})
+	want = append(want, fmt.Sprintf("glog_test.go:%d] async", func() int { d.Info("async"); return line() }()))
+	d.Flush(context.Background())
+	log.Flush()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.log.INFO.*"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Glob(%q): got %v, %v, want one file", dir, files, err)
+	}
+	b, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("ReadFile(%q): %v", files[0], err)
+	}
+	for _, w := range want {
+		if !strings.Contains(string(b), w) {
+			t.Errorf("Caller: produced unexpected output: got %q, want it to contain %q", b, w)
+		}
+	}
+}
@@ -19,24 +19,48 @@ import (
 	"errors"
 	"io"
 	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/google/deck"
 )
 
 // Init initializes the logger backend for use in a deck.
+//
+// If out has a Flush method, such as a *bufio.Writer, the backend implements deck.Flusher by
+// calling it. If out has a Sync method, such as an *os.File, the backend implements
+// deck.Syncer by calling it.
 func Init(out io.Writer, flags int) *Logger {
 	if flags == 0 {
 		flags = log.LstdFlags
 	}
+	w := &lockedWriter{w: out}
 	return &Logger{
-		debug:   log.New(out, TagDebug, flags),
-		info:    log.New(out, TagInfo, flags),
-		warning: log.New(out, TagWarning, flags),
-		error:   log.New(out, TagError, flags),
-		fatal:   log.New(out, TagFatal, flags),
+		out:     w,
+		debug:   log.New(w, TagDebug, flags),
+		info:    log.New(w, TagInfo, flags),
+		warning: log.New(w, TagWarning, flags),
+		error:   log.New(w, TagError, flags),
+		fatal:   log.New(w, TagFatal, flags),
 	}
 }
 
+// lockedWriter serializes access to the writer shared by each level's log.Logger.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
 var (
 	// TagDebug is the tag added to messages logged at the DEBUG level.
 	TagDebug = "DEBUG: "
@@ -52,6 +76,7 @@ var (
 
 // Logger is a log deck backend that passes logs through to Go's core log package.
 type Logger struct {
+	out     *lockedWriter
 	debug   *log.Logger
 	info    *log.Logger
 	warning *log.Logger
@@ -63,47 +88,180 @@ type Logger struct {
 // must be closed by the caller.
 func (l *Logger) Close() error { return nil }
 
+// Flush flushes the io.Writer passed to Init(), if it has a Flush method.
+func (l *Logger) Flush() error {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	if f, ok := l.out.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Sync commits the contents of the io.Writer passed to Init() to stable storage, if it has a
+// Sync method. Writers which can't be synced, such as a terminal or a pipe, are ignored.
+func (l *Logger) Sync() error {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	s, ok := l.out.w.(interface{ Sync() error })
+	if !ok {
+		return nil
+	}
+	if err := s.Sync(); err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, errors.ErrUnsupported) {
+		return err
+	}
+	return nil
+}
+
 type message struct {
-	level   deck.Level
-	message string
-	depth   int
-	parent  *Logger
+	level     deck.Level
+	message   string
+	time      time.Time
+	caller    deck.Caller
+	hasCaller bool
+	parent    *Logger
+	buf       []byte
+}
+
+// messages holds messages for reuse once they have been written.
+var messages = sync.Pool{
+	New: func() any { return &message{} },
 }
 
 // New creates a new logger message.
 func (l *Logger) New(lvl deck.Level, msg string) deck.Composer {
-	return &message{level: lvl, message: msg, parent: l}
+	m := messages.Get().(*message)
+	m.level, m.message, m.parent = lvl, msg, l
+	return m
 }
 
-// Depth affects certain flags including Llongfile and Lshortfile. Adding 3 excludes the frames in
-// logger and deck.go, so the user's code locations should be rendered by default.
-const depthOffset = 3
+// release returns m to the pool. Deck doesn't use a Composer after calling Write.
+func (m *message) release() {
+	buf := m.buf[:0]
+	if cap(buf) > 1024 {
+		buf = nil
+	}
+	*m = message{buf: buf}
+	messages.Put(m)
+}
 
 // Write flushes a stored log message.
 func (m *message) Write() error {
+	defer m.release()
+	var l *log.Logger
 	switch m.level {
 	case deck.DEBUG:
-		m.parent.debug.Output(m.depth+depthOffset, m.message)
+		l = m.parent.debug
 	case deck.INFO:
-		m.parent.info.Output(m.depth+depthOffset, m.message)
+		l = m.parent.info
 	case deck.WARNING:
-		m.parent.warning.Output(m.depth+depthOffset, m.message)
+		l = m.parent.warning
 	case deck.ERROR:
-		m.parent.error.Output(m.depth+depthOffset, m.message)
+		l = m.parent.error
 	case deck.FATAL:
-		m.parent.fatal.Output(m.depth+depthOffset, m.message)
+		l = m.parent.fatal
 	default: // any levels that don't map go to info
-		m.parent.info.Output(m.depth+depthOffset, m.message)
+		l = m.parent.info
 	}
-	return nil
+
+	// The time and caller were captured by the deck, so the header is formatted here rather
+	// than by the log package.
+	t := m.time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	file, line := "???", 0
+	if m.hasCaller {
+		file, line = m.caller.File, m.caller.Line
+	}
+	m.buf = appendHeader(m.buf[:0], t, l.Prefix(), l.Flags(), file, line)
+	m.buf = append(m.buf, m.message...)
+	if len(m.message) == 0 || m.message[len(m.message)-1] != '\n' {
+		m.buf = append(m.buf, '\n')
+	}
+	_, err := l.Writer().Write(m.buf)
+	return err
+}
+
+// appendHeader appends a log header to buf in the same format as the log package.
+func appendHeader(buf []byte, t time.Time, prefix string, flags int, file string, line int) []byte {
+	if flags&log.Lmsgprefix == 0 {
+		buf = append(buf, prefix...)
+	}
+	if flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		if flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if flags&log.Ldate != 0 {
+			year, month, day := t.Date()
+			buf = appendInt(buf, year, 4)
+			buf = append(buf, '/')
+			buf = appendInt(buf, int(month), 2)
+			buf = append(buf, '/')
+			buf = appendInt(buf, day, 2)
+			buf = append(buf, ' ')
+		}
+		if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+			hour, min, sec := t.Clock()
+			buf = appendInt(buf, hour, 2)
+			buf = append(buf, ':')
+			buf = appendInt(buf, min, 2)
+			buf = append(buf, ':')
+			buf = appendInt(buf, sec, 2)
+			if flags&log.Lmicroseconds != 0 {
+				buf = append(buf, '.')
+				buf = appendInt(buf, t.Nanosecond()/1e3, 6)
+			}
+			buf = append(buf, ' ')
+		}
+	}
+	if flags&(log.Lshortfile|log.Llongfile) != 0 {
+		if flags&log.Lshortfile != 0 {
+			file = filepath.Base(file)
+		}
+		buf = append(buf, file...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(line), 10)
+		buf = append(buf, ": "...)
+	}
+	if flags&log.Lmsgprefix != 0 {
+		buf = append(buf, prefix...)
+	}
+	return buf
 }
 
-// Compose composes the message prior to writing.
+// appendInt appends the non-negative integer i to buf, zero padded to at least wid digits, in
+// the same way as the log package.
+func appendInt(buf []byte, i, wid int) []byte {
+	var b [20]byte
+	bp := len(b) - 1
+	for i >= 10 || wid > 1 {
+		wid--
+		q := i / 10
+		b[bp] = byte('0' + i - q*10)
+		bp--
+		i = q
+	}
+	b[bp] = byte('0' + i)
+	return append(buf, b[bp:]...)
+}
+
+// Compose composes the message prior to writing. Any fields attached to the message are
+// appended to it as key=value pairs, followed on later lines by any details from
+// deck.AppendDetails, such as a stack captured with deck.Stack.
 func (m *message) Compose(s *deck.AttribStore) error {
-	id, ok := s.Load("Depth")
-	if !ok {
-		return errors.New("invalid Depth")
+	if fields := deck.Fields(s); len(fields) > 0 {
+		m.buf = append(m.buf[:0], strings.TrimSuffix(m.message, "\n")...)
+		m.buf = append(m.buf, ' ')
+		m.buf = deck.AppendFields(m.buf, fields)
+		m.message = string(m.buf)
+	}
+	if d := deck.AppendDetails(nil, s); len(d) > 0 {
+		m.message = strings.TrimSuffix(m.message, "\n") + "\n" + string(d)
+	}
+	if t, ok := deck.TimeKey.Get(s); ok {
+		m.time = t
 	}
-	m.depth = id.(int)
+	m.caller, m.hasCaller = deck.CallerKey.Get(s)
 	return nil
 }
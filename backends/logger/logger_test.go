@@ -0,0 +1,160 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/deck"
+)
+
+// line returns the line number of its caller.
+func line() int {
+	_, _, l, _ := runtime.Caller(1)
+	return l
+}
+
+func TestCaller(t *testing.T) {
+	var buf bytes.Buffer
+	d := deck.New()
+	d.Add(Init(&buf, log.Lshortfile))
+	tests := []struct {
+		desc string
+		f    func() int
+	}{
+		{"Info", func() int { d.Info("message"); return line() }},
+		{"Infof", func() int { d.Infof("message %d", 1); return line() }},
+		{"InfoA Go", func() int { d.InfoA("message").Go(); return line() }},
+		{"InfoA GoErr", func() int { d.InfoA("message").GoErr(); return line() }},
+		{"V Info", func() int { d.V(0).Info("message"); return line() }},
+		{"V InfofA", func() int { d.V(0).InfofA("message %d", 1).Go(); return line() }},
+	}
+	for _, async := range []bool{false, true} {
+		if async {
+			d.SetAsync(&deck.AsyncOptions{})
+		}
+		for _, tt := range tests {
+			buf.Reset()
+			want := fmt.Sprintf("INFO: logger_test.go:%d: ", tt.f())
+			d.Flush(context.Background())
+			if !strings.HasPrefix(buf.String(), want) {
+				t.Errorf("%s (async %t): produced unexpected caller: got %q, want prefix %q", tt.desc, async, buf.String(), want)
+			}
+		}
+	}
+	d.SetAsync(nil)
+}
+
+func TestHeader(t *testing.T) {
+	ts := time.Date(2026, time.March, 4, 5, 6, 7, 8000, time.UTC)
+	tests := []struct {
+		desc  string
+		flags int
+		want  string
+	}{
+		{"LstdFlags", log.LstdFlags | log.LUTC, "P: 2026/03/04 05:06:07 "},
+		{"Lmicroseconds", log.Ltime | log.Lmicroseconds | log.LUTC, "P: 05:06:07.000008 "},
+		{"Lshortfile", log.Lshortfile, "P: main.go:12: "},
+		{"Llongfile", log.Llongfile | log.Lmsgprefix, "/src/main.go:12: P: "},
+	}
+	for _, tt := range tests {
+		got := string(appendHeader(nil, ts, "P: ", tt.flags, "/src/main.go", 12))
+		if got != tt.want {
+			t.Errorf("%s: produced unexpected header: got %q, want %q", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	var buf bytes.Buffer
+	d := deck.New()
+	d.Add(Init(&buf, log.Lmsgprefix))
+	d.InfolnA("message").With(deck.Str("user", "alice"), deck.Str("path", "/a b")).Go()
+	want := "INFO: message user=alice path=\"/a b\"\n"
+	if buf.String() != want {
+		t.Errorf("Fields: produced unexpected output: got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDetails(t *testing.T) {
+	var buf bytes.Buffer
+	d := deck.New()
+	d.Add(Init(&buf, log.Lmsgprefix))
+	d.InfoA("message").With(deck.Stack()).Go()
+	want := "INFO: message\ngithub.com/google/deck/backends/logger.TestDetails\n\t"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("Stack: produced unexpected output: got %q, want prefix %q", buf.String(), want)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	var buf bytes.Buffer
+	d := deck.New()
+	d.Add(Init(bufio.NewWriter(&buf), log.Lmsgprefix))
+	d.Info("message")
+	if buf.Len() != 0 {
+		t.Fatalf("Info(): produced unexpected output before Flush: %q", buf.String())
+	}
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush(): produced unexpected error: %v", err)
+	}
+	if want := "INFO: message\n"; buf.String() != want {
+		t.Errorf("Flush(): produced unexpected output: got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestFatal(t *testing.T) {
+	var buf bytes.Buffer
+	d := deck.New()
+	d.Add(Init(bufio.NewWriter(&buf), log.Lmsgprefix))
+	exited := false
+	d.SetExitFunc(func(int) { exited = true })
+	d.Fatal("message")
+	if !exited {
+		t.Fatalf("Fatal(): did not call the exit function")
+	}
+	if want := "FATAL: message\n"; buf.String() != want {
+		t.Errorf("Fatal(): produced unexpected output: got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestSync(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	for _, out := range []*os.File{f, w} {
+		if err := Init(out, 0).Sync(); err != nil {
+			t.Errorf("Sync(%s): produced unexpected error: %v", out.Name(), err)
+		}
+	}
+}
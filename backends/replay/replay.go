@@ -17,9 +17,12 @@ package replay
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/deck"
 )
@@ -61,14 +64,43 @@ func (b Bundle) ContainsRE(re *regexp.Regexp) bool {
 	return false
 }
 
+// ContainsField searches the Bundle for a message with a field named key holding value.
+func (b Bundle) ContainsField(key string, value any) bool {
+	for _, a := range b {
+		for _, f := range a.Fields {
+			if f.Key == key && reflect.DeepEqual(f.Value, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Len returns the length of the Bundle.
 func (b Bundle) Len() int { return len(b) }
 
 // Log models a log entry as it's written to the Bundle. It tracks the log message but also other
-// metadata that we may want to recall later, like the deck Level.
+// metadata that we may want to recall later, like the deck Level, any fields attached
+// to the message, and the time and caller captured by the deck.
 type Log struct {
 	Level   deck.Level
 	Message string
+	Fields  []deck.KeyValue
+	Time    time.Time
+	Caller  deck.Caller
+}
+
+// Equal reports whether e and o record the same message. Time and Caller are only compared
+// when they are set in both, so that expected Logs needn't specify them. Equal is used by
+// go-cmp.
+func (e Log) Equal(o Log) bool {
+	if e.Level != o.Level || e.Message != o.Message || !reflect.DeepEqual(e.Fields, o.Fields) {
+		return false
+	}
+	if !e.Time.IsZero() && !o.Time.IsZero() && !e.Time.Equal(o.Time) {
+		return false
+	}
+	return e.Caller == deck.Caller{} || o.Caller == deck.Caller{} || e.Caller == o.Caller
 }
 
 // String stringifies Log objects for nicer printing.
@@ -81,6 +113,9 @@ func (e Log) String() string {
 		deck.FATAL:   "FATAL",
 		DEFAULT:      "DEFAULT",
 	}
+	if len(e.Fields) > 0 {
+		return fmt.Sprintf("%s: %q %s", levels[e.Level], e.Message, deck.FormatFields(e.Fields))
+	}
 	return fmt.Sprintf("%s: %q", levels[e.Level], e.Message)
 }
 
@@ -156,6 +191,9 @@ type message struct {
 	message string
 	parent  *Replay
 	level   deck.Level
+	fields  []deck.KeyValue
+	time    time.Time
+	caller  deck.Caller
 }
 
 // New creates a new replay message.
@@ -167,22 +205,29 @@ func (r *Replay) New(lvl deck.Level, msg string) deck.Composer {
 func (m *message) Write() error {
 	switch m.level {
 	case deck.DEBUG:
-		m.parent.append(Log{deck.DEBUG, m.message})
+		m.parent.append(m.log(deck.DEBUG))
 	case deck.INFO:
-		m.parent.append(Log{deck.INFO, m.message})
+		m.parent.append(m.log(deck.INFO))
 	case deck.WARNING:
-		m.parent.append(Log{deck.WARNING, m.message})
+		m.parent.append(m.log(deck.WARNING))
 	case deck.ERROR:
-		m.parent.append(Log{deck.ERROR, m.message})
+		m.parent.append(m.log(deck.ERROR))
 	case deck.FATAL:
-		m.parent.append(Log{deck.FATAL, m.message})
+		m.parent.append(m.log(deck.FATAL))
 	default:
-		m.parent.append(Log{DEFAULT, m.message})
+		m.parent.append(m.log(DEFAULT))
 	}
 	return nil
 }
 
-// Compose satisfies the composer interface. In the replay backend it has no other purpose.
+func (m *message) log(lvl deck.Level) Log {
+	return Log{Level: lvl, Message: m.message, Fields: m.fields, Time: m.time, Caller: m.caller}
+}
+
+// Compose records any fields attached to the message, and its time and caller.
 func (m *message) Compose(s *deck.AttribStore) error {
+	m.fields = slices.Clone(deck.Fields(s))
+	m.time, _ = deck.TimeKey.Get(s)
+	m.caller, _ = deck.CallerKey.Get(s)
 	return nil
 }
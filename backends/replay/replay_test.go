@@ -15,8 +15,10 @@
 package replay
 
 import (
+	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/google/deck"
 	"github.com/google/go-cmp/cmp"
@@ -172,21 +174,21 @@ func TestLevels(t *testing.T) {
 		{
 			"error messages",
 			[]string{"error message one", "another error"},
-			Bundle{Log{deck.ERROR, "error message one"}, Log{deck.ERROR, "another error"}},
+			Bundle{Log{Level: deck.ERROR, Message: "error message one"}, Log{Level: deck.ERROR, Message: "another error"}},
 			d.Error,
 			r.Error,
 		},
 		{
 			"info messages",
 			[]string{"info message one"},
-			Bundle{Log{deck.INFO, "info message one"}},
+			Bundle{Log{Level: deck.INFO, Message: "info message one"}},
 			d.Info,
 			r.Info,
 		},
 		{
 			"warning messages",
 			[]string{"warning message one", "warning message two"},
-			Bundle{Log{deck.WARNING, "warning message one"}, Log{deck.WARNING, "warning message two"}},
+			Bundle{Log{Level: deck.WARNING, Message: "warning message one"}, Log{Level: deck.WARNING, Message: "warning message two"}},
 			d.Warning,
 			r.Warning,
 		},
@@ -227,3 +229,24 @@ func TestReset(t *testing.T) {
 		t.Errorf("Reset() failed to reset logs as expected")
 	}
 }
+
+func TestTimeAndCaller(t *testing.T) {
+	d := deck.New()
+	r := Init()
+	d.Add(r)
+	start := time.Now()
+	d.Info("message")
+	all := r.All()
+	if all.Len() != 1 {
+		t.Fatalf("All(): produced unexpected size of results: got %d, want 1", all.Len())
+	}
+	if got := all[0].Time; got.Before(start) {
+		t.Errorf("Time: produced unexpected time: got %v, want after %v", got, start)
+	}
+	if got := filepath.Base(all[0].Caller.File); got != "replay_test.go" {
+		t.Errorf("Caller: produced unexpected file: got %q, want replay_test.go", got)
+	}
+	if !all[0].Equal(Log{Level: deck.INFO, Message: "message"}) {
+		t.Errorf("Equal(): produced unexpected result: got false, want true")
+	}
+}
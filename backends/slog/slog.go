@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slog provides a deck backend which forwards messages to a log/slog Handler.
+//
+// Any slog.Handler may be used, including slog.JSONHandler, slog.TextHandler and third party
+// handlers. Deck attributes are converted to slog attributes, and each slog.Record carries the
+// time and caller captured by the deck, which honor deck's Depth attribute.
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/deck"
+)
+
+// LevelFatal is the slog level used for messages logged at deck's FATAL level.
+const LevelFatal = slog.LevelError + 4
+
+// Init initializes the slog backend for use in a deck.
+func Init(h slog.Handler) *Slog {
+	return &Slog{handler: h}
+}
+
+// Slog is a log deck backend that passes logs through to a slog.Handler.
+type Slog struct {
+	handler slog.Handler
+}
+
+// Close closes the slog backend. The slog.Handler passed to Init() is not closed and must be
+// closed by the caller, if required.
+func (s *Slog) Close() error { return nil }
+
+type message struct {
+	parent  *Slog
+	level   slog.Level
+	message string
+	time    time.Time
+	attrs   []slog.Attr
+	pc      uintptr
+}
+
+// New creates a new slog message.
+func (s *Slog) New(lvl deck.Level, msg string) deck.Composer {
+	return &message{parent: s, level: Level(lvl), message: msg}
+}
+
+// Level maps a deck level onto a slog level. Levels that don't map are treated as INFO.
+func Level(lvl deck.Level) slog.Level {
+	switch lvl {
+	case deck.DEBUG:
+		return slog.LevelDebug
+	case deck.INFO:
+		return slog.LevelInfo
+	case deck.WARNING:
+		return slog.LevelWarn
+	case deck.ERROR:
+		return slog.LevelError
+	case deck.FATAL:
+		return LevelFatal
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Compose converts the attributes of the message into slog attributes.
+//
+// deck.TimeKey and deck.CallerKey set the time and caller of the record rather than being
+// forwarded, and Depth is dropped as the deck has already applied it. A stack captured with
+// deck.Stack is forwarded as a string formatted by deck.FormatStack. Other attributes are
+// forwarded ordered by key, followed by the message's fields in the order they were added.
+func (m *message) Compose(s *deck.AttribStore) error {
+	if t, ok := deck.TimeKey.Get(s); ok {
+		m.time = t
+	} else {
+		m.time = time.Now()
+	}
+	if c, ok := deck.CallerKey.Get(s); ok {
+		m.pc = c.PC
+	}
+	s.Range(func(k, v any) bool {
+		key, ok := k.(string)
+		if !ok {
+			return true
+		}
+		switch key {
+		case deck.DepthKey.String(), deck.TimeKey.String(), deck.CallerKey.String():
+		case deck.StackKey.String():
+			if pcs, ok := v.([]uintptr); ok {
+				m.attrs = append(m.attrs, slog.String(key, deck.FormatStack(pcs)))
+			}
+		default:
+			m.attrs = append(m.attrs, slog.Any(key, v))
+		}
+		return true
+	})
+	sort.Slice(m.attrs, func(i, j int) bool { return m.attrs[i].Key < m.attrs[j].Key })
+	for _, f := range deck.Fields(s) {
+		m.attrs = append(m.attrs, slog.Any(f.Key, f.Value))
+	}
+	return nil
+}
+
+// Write passes the message to the slog.Handler.
+func (m *message) Write() error {
+	ctx := context.Background()
+	if !m.parent.handler.Enabled(ctx, m.level) {
+		return nil
+	}
+	r := slog.NewRecord(m.time, m.level, m.message, m.pc)
+	r.AddAttrs(m.attrs...)
+	return m.parent.handler.Handle(ctx, r)
+}
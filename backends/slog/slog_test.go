@@ -0,0 +1,94 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/deck"
+)
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	d := deck.New()
+	d.Add(Init(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true, Level: slog.LevelDebug})))
+	tests := []struct {
+		desc      string
+		f         func()
+		wantLevel string
+		wantAttrs map[string]any
+	}{
+		{"Debug", func() { d.Debug("message") }, "DEBUG", nil},
+		{"Warning", func() { d.Warning("message") }, "WARN", nil},
+		{"Fatal", func() { d.FatalA("message").Go() }, "ERROR+4", nil},
+		{
+			"attributes",
+			func() { d.InfoA("message").With(deck.V(0), func(s *deck.AttribStore) { s.Store("k", "v") }).Go() },
+			"INFO",
+			map[string]any{"Verbosity": 0.0, "k": "v"},
+		},
+		{
+			"fields",
+			func() { d.InfoA("message").With(deck.Str("user", "alice"), deck.Int("n", 2)).Go() },
+			"INFO",
+			map[string]any{"user": "alice", "n": 2.0},
+		},
+		{
+			// Sampling state is private to the deck; a nil want means the attribute is absent.
+			"sampling",
+			func() { d.InfoA("message").With(deck.Every(10), deck.SampleKey("k")).Go() },
+			"INFO",
+			map[string]any{"Sampler": nil, "SampleKey": nil},
+		},
+	}
+	d.SetExitFunc(func(int) {})
+	for _, tt := range tests {
+		buf.Reset()
+		tt.f()
+		var got map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("%s: produced invalid JSON %q: %v", tt.desc, buf.String(), err)
+		}
+		if got[slog.LevelKey] != tt.wantLevel {
+			t.Errorf("%s: produced unexpected level: got %v, want %v", tt.desc, got[slog.LevelKey], tt.wantLevel)
+		}
+		if got[slog.MessageKey] != "message" {
+			t.Errorf("%s: produced unexpected message: got %v, want %v", tt.desc, got[slog.MessageKey], "message")
+		}
+		src, _ := got[slog.SourceKey].(map[string]any)
+		if file, _ := src["file"].(string); !strings.HasSuffix(file, "slog_test.go") {
+			t.Errorf("%s: produced unexpected source: got %v, want slog_test.go", tt.desc, src["file"])
+		}
+		for k, v := range tt.wantAttrs {
+			if got[k] != v {
+				t.Errorf("%s: produced unexpected attribute %q: got %v, want %v", tt.desc, k, got[k], v)
+			}
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	d := deck.New()
+	d.Add(Init(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	d.Info("info message")
+	if buf.Len() != 0 {
+		t.Errorf("Info(): produced unexpected output for disabled level: %q", buf.String())
+	}
+}
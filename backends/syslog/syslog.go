@@ -19,7 +19,12 @@
 package syslog
 
 import (
+	"fmt"
 	"log/syslog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
 	"github.com/google/deck"
 )
@@ -71,6 +76,13 @@ func Init(tag string, facility syslog.Priority) (*Syslog, error) {
 // Syslog is a log deck backend that passes logs through to the syslog package.
 type Syslog struct {
 	handle *syslog.Writer
+	caller atomic.Bool
+}
+
+// SetCaller makes the backend prefix each message with the file name and line number of the
+// code which logged it, as captured by the deck, such as "main.go:12: ". It is off by default.
+func (s *Syslog) SetCaller(enabled bool) {
+	s.caller.Store(enabled)
 }
 
 // Close closes the syslog backend.
@@ -93,22 +105,68 @@ func (s *Syslog) New(lvl deck.Level, msg string) deck.Composer {
 func (m *message) Write() error {
 	switch m.level {
 	case deck.DEBUG:
-		m.parent.handle.Debug(m.message)
+		return m.parent.handle.Debug(m.message)
 	case deck.INFO:
-		m.parent.handle.Info(m.message)
+		return m.parent.handle.Info(m.message)
 	case deck.WARNING:
-		m.parent.handle.Warning(m.message)
+		return m.parent.handle.Warning(m.message)
 	case deck.ERROR:
-		m.parent.handle.Err(m.message)
+		return m.parent.handle.Err(m.message)
 	case deck.FATAL:
-		m.parent.handle.Crit(m.message)
+		return m.parent.handle.Crit(m.message)
 	default:
-		m.parent.handle.Info(m.message)
+		return m.parent.handle.Info(m.message)
 	}
-	return nil
 }
 
-// Compose composes the message prior to writing.
+// Compose composes the message prior to writing. Any fields attached to the message are
+// rendered as a bracketed list of key="value" pairs preceding the message text, followed by the
+// caller if enabled with SetCaller. The prefix is part of the message text: log/syslog does not
+// send RFC 5424 structured data.
 func (m *message) Compose(s *deck.AttribStore) error {
+	if c, ok := deck.CallerKey.Get(s); ok && m.parent.caller.Load() {
+		m.message = filepath.Base(c.File) + ":" + strconv.Itoa(c.Line) + ": " + m.message
+	}
+	if fields := deck.Fields(s); len(fields) > 0 {
+		m.message = fieldPrefix(fields) + " " + m.message
+	}
 	return nil
 }
+
+// fieldPrefix renders fields as a bracketed list of key="value" pairs, such as
+// [user="alice" attempts="3"].
+func fieldPrefix(fields []deck.KeyValue) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(fieldName(f.Key) + `="`)
+		fieldValue.WriteString(&b, fmt.Sprint(f.Value))
+		b.WriteString(`"`)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// fieldValue escapes the characters which would otherwise end a value or the prefix early.
+var fieldValue = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
+
+// fieldName converts key to at most 32 printable ASCII characters, excluding '=', ' ', ']' and
+// '"', so that the prefix can be split back into pairs. Other characters are replaced with '_'.
+func fieldName(key string) string {
+	b := []byte(key)
+	if len(b) > 32 {
+		b = b[:32]
+	}
+	for i, c := range b {
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 {
+		return "_"
+	}
+	return string(b)
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package syslog
+
+import (
+	"testing"
+
+	"github.com/google/deck"
+)
+
+func TestFieldPrefix(t *testing.T) {
+	tests := []struct {
+		in   []deck.KeyValue
+		want string
+	}{
+		{[]deck.KeyValue{{Key: "user", Value: "alice"}}, `[user="alice"]`},
+		{[]deck.KeyValue{{Key: "n", Value: 1}, {Key: "q", Value: `a"b\c]`}}, `[n="1" q="a\"b\\c\]"]`},
+		{[]deck.KeyValue{{Key: "bad key=", Value: ""}}, `[bad_key_=""]`},
+	}
+	for _, tt := range tests {
+		if got := fieldPrefix(tt.in); got != tt.want {
+			t.Errorf("fieldPrefix(%v): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCaller(t *testing.T) {
+	s := &deck.AttribStore{}
+	deck.CallerKey.Set(s, deck.Caller{File: "/src/main.go", Line: 12})
+	deck.Str("user", "alice")(s)
+	tests := []struct {
+		desc    string
+		enabled bool
+		want    string
+	}{
+		{"disabled", false, `[user="alice"] message`},
+		{"enabled", true, `[user="alice"] main.go:12: message`},
+	}
+	for _, tt := range tests {
+		b := &Syslog{}
+		b.SetCaller(tt.enabled)
+		m := b.New(deck.INFO, "message").(*message)
+		if err := m.Compose(s); err != nil {
+			t.Fatalf("%s: Compose(): produced unexpected error: %v", tt.desc, err)
+		}
+		if m.message != tt.want {
+			t.Errorf("%s: Compose(): produced unexpected message: got %q, want %q", tt.desc, m.message, tt.want)
+		}
+	}
+}
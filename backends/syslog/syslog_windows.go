@@ -27,3 +27,6 @@ type Syslog struct{}
 func Init(tag string, facility int) (*Syslog, error) {
 	return nil, errors.New("not supported on this platform")
 }
+
+// SetCaller is not supported on this platform.
+func (s *Syslog) SetCaller(enabled bool) {}
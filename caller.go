@@ -0,0 +1,91 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// callers caches the Caller of each call site, by program counter.
+var callers sync.Map
+
+// callerAt returns the Caller for the program counter pc, as returned by runtime.Callers.
+func callerAt(pc uintptr) *Caller {
+	if c, ok := callers.Load(pc); ok {
+		return c.(*Caller)
+	}
+	f, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	c, _ := callers.LoadOrStore(pc, &Caller{PC: pc, File: f.File, Line: f.Line, Function: f.Function})
+	return c.(*Caller)
+}
+
+// callerPC returns the program counter of the call site skip frames above the caller of
+// callerPC, as returned by runtime.Callers.
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(skip+2, pcs[:])
+	return pcs[0]
+}
+
+// capture records the time and caller of l, its stack if requested with Stack, and its
+// goroutine if goroutine is set, when l is committed. Values which are already set are kept.
+// capture must be called by dispatch.
+func (l *record) capture(goroutine bool) {
+	s := &l.attributes
+	if !s.hasTime {
+		s.time, s.hasTime = time.Now(), true
+	}
+	// Skip capture, dispatch and Go to reach the code which committed l.
+	depth, _ := DepthKey.Get(s)
+	if s.caller == nil {
+		if pc := callerPC(depth + 3); pc != 0 {
+			s.caller = callerAt(pc)
+		}
+	}
+	if pcs, ok := StackKey.Get(s); ok && pcs == nil {
+		StackKey.Set(s, stack(depth+3))
+	}
+	if goroutine && s.goroutine == 0 {
+		s.goroutine = goroutineID()
+	}
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the header of its stack
+// trace, such as "goroutine 18 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// SetGoroutineIDs enables or disables recording goroutine IDs in the default deck.
+func SetGoroutineIDs(enabled bool) {
+	defaultDeck.SetGoroutineIDs(enabled)
+}
+
+// SetGoroutineIDs makes the deck record the ID of the goroutine which commits each message,
+// under GoroutineKey. Go provides no cheap way to find the ID, so it is off by default.
+func (d *Deck) SetGoroutineIDs(enabled bool) {
+	d.root().update(func(c *config) { c.goroutines = enabled })
+}
@@ -16,10 +16,15 @@
 package deck
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"os"
+	"slices"
 	"sync"
+	"sync/atomic"
 )
 
 // A Level is a recognized log level (Info, Error, etc). Behavior of a given level is
@@ -58,19 +63,77 @@ type Composer interface {
 	Write() error
 }
 
+// An ErrorHandler receives errors returned by a backend while composing or writing a
+// message at the given level.
+type ErrorHandler func(backend Backend, lvl Level, err error)
+
 // Backend is the interface that identifies logging backends with NewMessage and Close methods.
 type Backend interface {
 	New(Level, string) Composer
 	Close() error
 }
 
+// Flusher is an optional interface implemented by backends which buffer their output. Flush
+// writes any buffered messages to the backend's destination.
+type Flusher interface {
+	Flush() error
+}
+
+// Syncer is an optional interface implemented by backends which can commit their output to
+// stable storage, such as by calling fsync.
+type Syncer interface {
+	Sync() error
+}
+
 // The Deck is the highest level of the logging hierarchy, consisting of one or more backends.
 // All logs written to the deck get flushed to each backend. Multiple decks can be configured with
 // their own sets of backends.
 type Deck struct {
-	backends  []Backend
-	verbosity int
-	mu        sync.Mutex
+	config     atomic.Pointer[config]
+	onError    atomic.Pointer[ErrorHandler]
+	handling   atomic.Int32 // the number of error handler calls running
+	handlers   sync.Map     // map[uint64]struct{}, the goroutines running the error handler
+	exit       func(code int)
+	async      atomic.Pointer[asyncQueue]
+	dropped    atomic.Uint64
+	vmodule    atomic.Pointer[vmodule]
+	dedup      atomic.Pointer[dedup]
+	samples    sync.Map // map[sampleID]*sampleState
+	suppressed atomic.Uint64
+	mu         sync.Mutex // serializes changes to config
+
+	parent *Deck    // the deck sharing its backends and settings, for child decks
+	attrs  []Attrib // attributes applied to every message from a child deck
+}
+
+// A config is an immutable snapshot of the settings of a deck which are read while logging.
+// Changes replace the whole snapshot, so logging never waits on a lock.
+type config struct {
+	backends   []*Handle
+	middleware []Middleware
+	redactor   *Redactor
+	verbosity  int
+	level      Level
+	goroutines bool
+}
+
+var emptyConfig = &config{}
+
+// load returns the current configuration of the deck.
+func (d *Deck) load() *config {
+	if c := d.config.Load(); c != nil {
+		return c
+	}
+	return emptyConfig
+}
+
+// update applies f to a copy of the deck's configuration, and stores the result.
+func (d *Deck) update(f func(c *config)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c := *d.load()
+	f(&c)
+	d.config.Store(&c)
 }
 
 // New returns a new initialized log deck.
@@ -78,21 +141,308 @@ func New() *Deck {
 	return &Deck{}
 }
 
+// With returns a child of the default deck with preset attributes.
+func With(attrs ...Attrib) *Deck {
+	return defaultDeck.With(attrs...)
+}
+
+// With returns a lightweight child deck which applies attrs to every message it creates.
+//
+// The child shares the backends, verbosity and other settings of its parent; configuring
+// or closing the child configures or closes the parent. Attributes added to an individual
+// message with Log.With take precedence over the preset attributes.
+//
+//	reqLog := d.With(eventlog.EventID(42))
+//	reqLog.Info("handling request")
+func (d *Deck) With(attrs ...Attrib) *Deck {
+	c := &Deck{parent: d.root()}
+	c.attrs = append(c.attrs, d.attrs...)
+	c.attrs = append(c.attrs, attrs...)
+	return c
+}
+
+// root returns the deck holding the backends and settings used by d.
+func (d *Deck) root() *Deck {
+	if d.parent != nil {
+		return d.parent
+	}
+	return d
+}
+
 // Add adds a backend to the default log deck.
-func Add(b Backend) {
-	defaultDeck.Add(b)
+func Add(b Backend) *Handle {
+	return defaultDeck.Add(b)
 }
 
-// Add adds an additional backend to the deck.
-func (d *Deck) Add(b Backend) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-	d.backends = append(d.backends, b)
+// Add adds an additional backend to the deck. The returned Handle may be used to remove or
+// replace the backend later.
+func (d *Deck) Add(b Backend) *Handle {
+	return d.AddWithOptions(b)
+}
+
+// AddWithOptions adds a backend to the default log deck with the given options.
+func AddWithOptions(b Backend, opts ...BackendOption) *Handle {
+	return defaultDeck.AddWithOptions(b, opts...)
+}
+
+// AddWithOptions adds an additional backend to the deck with the given options, such as a
+// minimum level which applies to that backend only.
+//
+//	d.AddWithOptions(sl, deck.MinLevel(deck.WARNING), deck.MaxVerbosity(0))
+func (d *Deck) AddWithOptions(b Backend, opts ...BackendOption) *Handle {
+	h := newHandle("", b)
+	for _, o := range opts {
+		o(h)
+	}
+	d.root().update(func(c *config) {
+		c.backends = append(c.backends[:len(c.backends):len(c.backends)], h)
+	})
+	return h
+}
+
+// AddNamed adds a named backend to the default log deck.
+func AddNamed(name string, b Backend) (*Handle, error) {
+	return defaultDeck.AddNamed(name, b)
+}
+
+// AddNamed adds an additional backend to the deck under name, which can later be passed to
+// Lookup. It fails if name is empty or the deck already has a backend with the same name.
+func (d *Deck) AddNamed(name string, b Backend) (*Handle, error) {
+	if name == "" {
+		return nil, errors.New("deck: a backend name must not be empty")
+	}
+	var err error
+	h := newHandle(name, b)
+	d.root().update(func(c *config) {
+		for _, o := range c.backends {
+			if o.name == name {
+				err = fmt.Errorf("deck: a backend named %q already exists", name)
+				return
+			}
+		}
+		c.backends = append(c.backends[:len(c.backends):len(c.backends)], h)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Remove removes a backend from the default log deck.
+func Remove(h *Handle) error {
+	return defaultDeck.Remove(h)
+}
+
+// Remove removes the backend identified by h from the deck and closes it, returning the
+// error from Close.
+//
+// Messages already being written to the backend are allowed to finish before it is closed,
+// and no further messages are written to it, so Remove is safe to call while other
+// goroutines are logging. Removing a backend which is not attached to the deck does nothing.
+func (d *Deck) Remove(h *Handle) error {
+	found := false
+	d.root().update(func(c *config) {
+		i := slices.Index(c.backends, h)
+		if found = i >= 0; found {
+			c.backends = slices.Delete(slices.Clone(c.backends), i, i+1)
+		}
+	})
+	if !found {
+		return nil
+	}
+	h.retire()
+	return h.backend.Close()
+}
+
+// Replace replaces a backend in the default log deck.
+func Replace(old *Handle, b Backend) (*Handle, error) {
+	return defaultDeck.Replace(old, b)
+}
+
+// Replace replaces the backend identified by old with b, keeping its name, position, level
+// and verbosity, and returns a Handle for b. The old backend is closed as with Remove, and
+// any error from its Close method is returned along with the new Handle.
+//
+// Every message is written to either the old or the new backend, but not both. Replace
+// fails if old is nil or not attached to the deck.
+func (d *Deck) Replace(old *Handle, b Backend) (*Handle, error) {
+	if old == nil {
+		return nil, errors.New("deck: no backend to replace")
+	}
+	h := newHandle(old.name, b)
+	h.SetLevel(old.Level())
+	h.SetVerbosity(old.Verbosity())
+	found := false
+	d.root().update(func(c *config) {
+		i := slices.Index(c.backends, old)
+		if found = i >= 0; found {
+			c.backends = slices.Clone(c.backends)
+			c.backends[i] = h
+		}
+	})
+	if !found {
+		return nil, fmt.Errorf("deck: backend %T is not attached to the deck", old.backend)
+	}
+	// Messages which find old retired are written to h instead.
+	old.next.Store(h)
+	old.retire()
+	return h, old.backend.Close()
+}
+
+// Backends returns the backends attached to the default log deck.
+func Backends() []Backend {
+	return defaultDeck.Backends()
+}
+
+// Backends returns the backends attached to the deck, in the order they were added.
+func (d *Deck) Backends() []Backend {
+	var backends []Backend
+	for _, h := range d.root().load().backends {
+		backends = append(backends, h.backend)
+	}
+	return backends
+}
+
+// Lookup returns the backend added to the default log deck under name.
+func Lookup(name string) (*Handle, bool) {
+	return defaultDeck.Lookup(name)
+}
+
+// Lookup returns the Handle of the backend added to the deck under name with AddNamed.
+func (d *Deck) Lookup(name string) (*Handle, bool) {
+	for _, h := range d.root().load().backends {
+		if h.name != "" && h.name == name {
+			return h, true
+		}
+	}
+	return nil, false
+}
+
+// A Handle identifies a backend attached to a deck.
+type Handle struct {
+	name      string
+	backend   Backend
+	level     atomic.Uint32
+	verbosity atomic.Int64
+
+	active  atomic.Int64           // messages being written to the backend
+	retired atomic.Bool            // set once the backend has been removed from its deck
+	idle    chan struct{}          // signaled when active reaches zero after retirement
+	next    atomic.Pointer[Handle] // the replacement of a backend retired by Replace
+}
+
+func newHandle(name string, b Backend) *Handle {
+	h := &Handle{name: name, backend: b, idle: make(chan struct{}, 1)}
+	h.verbosity.Store(math.MaxInt)
+	return h
+}
+
+// A BackendOption configures a backend added with AddWithOptions.
+type BackendOption func(*Handle)
+
+// MinLevel sets the minimum level of messages written to a backend.
+func MinLevel(lvl Level) BackendOption {
+	return func(h *Handle) {
+		h.SetLevel(lvl)
+	}
+}
+
+// MaxVerbosity sets the maximum verbosity of messages written to a backend.
+func MaxVerbosity(v int) BackendOption {
+	return func(h *Handle) {
+		h.SetVerbosity(v)
+	}
+}
+
+// SetLevel sets the minimum level of messages written to the backend. It may be called while
+// the deck is in use.
+//
+// The backend's level applies in addition to the deck's level, and FATAL messages are always
+// written. The default level is DEBUG.
+func (h *Handle) SetLevel(lvl Level) {
+	h.level.Store(uint32(lvl))
+}
+
+// Level returns the minimum level of messages written to the backend.
+func (h *Handle) Level() Level {
+	return Level(h.level.Load())
+}
+
+// SetVerbosity sets the maximum verbosity of messages written to the backend. It may be called
+// while the deck is in use.
+//
+// The backend's verbosity applies in addition to the deck's verbosity, so it can only narrow
+// the set of messages the backend receives. FATAL messages are always written. By default,
+// the backend accepts every message committed by the deck.
+func (h *Handle) SetVerbosity(v int) {
+	h.verbosity.Store(int64(v))
+}
+
+// Verbosity returns the maximum verbosity of messages written to the backend.
+func (h *Handle) Verbosity() int {
+	return int(h.verbosity.Load())
+}
+
+// accepts reports whether a message with the given level and verbosity should be written to
+// the backend.
+func (h *Handle) accepts(lvl Level, v int) bool {
+	if lvl >= FATAL {
+		return true
+	}
+	return lvl >= h.Level() && int64(v) <= h.verbosity.Load()
+}
+
+// Name returns the name the backend was added under, or "" if it was added with Add.
+func (h *Handle) Name() string {
+	return h.name
+}
+
+// Backend returns the backend identified by h.
+func (h *Handle) Backend() Backend {
+	return h.backend
+}
+
+// acquire reports whether a message may be written to the backend, and if so marks the
+// write as in progress until release is called.
+func (h *Handle) acquire() bool {
+	h.active.Add(1)
+	if h.retired.Load() {
+		h.release()
+		return false
+	}
+	return true
+}
+
+func (h *Handle) release() {
+	if h.active.Add(-1) == 0 && h.retired.Load() {
+		select {
+		case h.idle <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// current returns the Handle which messages for h should be written to, marked in progress
+// as by acquire, or nil if h has been removed. If h has been replaced, this is its
+// replacement.
+func (h *Handle) current() *Handle {
+	for h != nil && !h.acquire() {
+		h = h.next.Load()
+	}
+	return h
+}
+
+// retire stops further writes to the backend, and waits for those in progress to finish.
+func (h *Handle) retire() {
+	h.retired.Store(true)
+	for h.active.Load() > 0 {
+		<-h.idle
+	}
 }
 
 // SetVerbosity sets the internal verbosity level of the default deck.
 func SetVerbosity(v int) {
-	defaultDeck.verbosity = v
+	defaultDeck.SetVerbosity(v)
 }
 
 // SetVerbosity sets the internal verbosity level of the deck.
@@ -100,23 +450,231 @@ func SetVerbosity(v int) {
 // Messages are committed if the message's own verbosity level (default 0) is
 // equal to or less than the deck's configured level.
 func (d *Deck) SetVerbosity(v int) {
-	d.verbosity = v
+	d.root().update(func(c *config) { c.verbosity = v })
+}
+
+// SetLevel sets the minimum level of the default deck.
+func SetLevel(lvl Level) {
+	defaultDeck.SetLevel(lvl)
+}
+
+// SetLevel sets the minimum level of the deck.
+//
+// Messages below the minimum level are discarded before any formatting takes place, so
+// calls such as Debugf can be left in place at little cost. FATAL messages are always
+// committed. The default level is DEBUG.
+func (d *Deck) SetLevel(lvl Level) {
+	d.root().update(func(c *config) { c.level = lvl })
+}
+
+// Level returns the minimum level of the deck.
+func (d *Deck) Level() Level {
+	return d.root().load().level
+}
+
+// SetErrorHandler sets the error handler of the default deck.
+func SetErrorHandler(h ErrorHandler) {
+	defaultDeck.SetErrorHandler(h)
+}
+
+// SetErrorHandler sets a function to be called whenever a backend returns an error
+// from Compose or Write. A nil handler discards errors, which is the default.
+//
+// The handler may log to the same deck. Errors raised by the handler's own messages are
+// not passed back to it, so a failing backend cannot cause unbounded recursion. Errors from
+// other goroutines are still reported, so the handler may run concurrently and must be safe
+// for concurrent use.
+func (d *Deck) SetErrorHandler(h ErrorHandler) {
+	d.root().onError.Store(&h)
+}
+
+func (d *Deck) handleError(b Backend, lvl Level, err error) {
+	h := d.onError.Load()
+	if h == nil || *h == nil || d.inErrorHandler() {
+		return
+	}
+	id := goroutineID()
+	d.handlers.Store(id, struct{}{})
+	d.handling.Add(1)
+	defer func() {
+		d.handling.Add(-1)
+		d.handlers.Delete(id)
+	}()
+	(*h)(b, lvl, err)
+}
+
+// inErrorHandler reports whether the calling goroutine is running the error handler. Looking
+// up the goroutine is only needed while some handler is running.
+func (d *Deck) inErrorHandler() bool {
+	if d.handling.Load() == 0 {
+		return false
+	}
+	_, ok := d.handlers.Load(goroutineID())
+	return ok
+}
+
+// SetExitFunc sets the exit function of the default deck.
+func SetExitFunc(f func(code int)) {
+	defaultDeck.SetExitFunc(f)
+}
+
+// SetExitFunc sets the function used to terminate the program once a FATAL message has
+// been committed. The default is os.Exit. Tests can substitute a function that records
+// the call instead of exiting.
+func (d *Deck) SetExitFunc(f func(code int)) {
+	d = d.root()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.exit = f
+}
+
+// fatal flushes and closes all backends in the deck and terminates the program.
+func (d *Deck) fatal() {
+	d.mu.Lock()
+	exit := d.exit
+	d.mu.Unlock()
+	if exit == nil {
+		exit = os.Exit
+	}
+	// Flush drains any summary of repeated messages and the asynchronous queue, then flushes
+	// and syncs the backends, as Close alone does not write out buffered output.
+	d.Flush(context.Background())
+	d.Close()
+	exit(1)
+}
+
+// enabled reports whether messages at lvl pass the deck's minimum level.
+func (d *Deck) enabled(lvl Level) bool {
+	return lvl >= d.root().load().level || lvl >= FATAL
 }
 
-func (d *Deck) mkLog(lvl Level, message string) *Log {
-	msg := NewLog(d.verbosity)
+// nopRecord is returned in place of messages that are below the deck's minimum level.
+var nopRecord = &record{disabled: true}
+
+// nopLog is a Log of nopRecord.
+var nopLog = &Log{record: nopRecord}
+
+func (d *Deck) logPrint(lvl Level, message ...any) *record {
+	if !d.enabled(lvl) {
+		return nopRecord
+	}
+	return d.mkRecord(lvl, sprint(message...))
+}
 
-	if len(d.backends) < 1 {
+// sprint is fmt.Sprint, without the copy for the common case of a single string.
+func sprint(message ...any) string {
+	if len(message) == 1 {
+		if s, ok := message[0].(string); ok {
+			return s
+		}
+	}
+	return fmt.Sprint(message...)
+}
+
+func (d *Deck) logPrintf(lvl Level, format string, message ...any) *record {
+	if !d.enabled(lvl) {
+		return nopRecord
+	}
+	return d.mkRecord(lvl, fmt.Sprintf(format, message...))
+}
+
+func (d *Deck) logPrintln(lvl Level, message ...any) *record {
+	if !d.enabled(lvl) {
+		return nopRecord
+	}
+	return d.mkRecord(lvl, fmt.Sprintln(message...))
+}
+
+// mkRecord returns a record holding message, printing message with the log package if the
+// deck has no backends.
+func (d *Deck) mkRecord(lvl Level, message string) *record {
+	msg := d.newRecord(lvl, message)
+	if c := msg.deck.load(); len(c.backends) < 1 {
+		if c.redactor != nil {
+			message = c.redactor.Redact(message)
+		}
 		fmt.Fprintln(os.Stderr, "WARNING: no backends configured, printing to log")
 		log.Print(message)
 	}
+	return msg
+}
 
-	for _, b := range d.backends {
-		msg.backends = append(msg.backends, b.New(lvl, message))
+// newRecord returns a record from the pool holding message, with the deck's attributes
+// applied.
+func (d *Deck) newRecord(lvl Level, message string) *record {
+	r := d.root()
+	msg := recordPool.Get().(*record)
+	c := r.load()
+	msg.verbosity = c.verbosity
+	msg.deck = r
+	msg.level = lvl
+	msg.message = message
+	for _, a := range d.attrs {
+		a(&msg.attributes)
 	}
 	return msg
 }
 
+// DebugA constructs a message in the default deck at the DEBUG level.
+func DebugA(message ...any) *Log {
+	return defaultDeck.DebugA(message...)
+}
+
+// Debug immediately logs a message with no attributes to the default deck at the DEBUG level.
+func Debug(message ...any) {
+	defaultDeck.DebugA(message...).With(Depth(1)).Go()
+}
+
+// DebugA constructs a message at the DEBUG level.
+func (d *Deck) DebugA(message ...any) *Log {
+	return newLog(d.logPrint(DEBUG, message...))
+}
+
+// Debug immediately logs a message with no attributes at the DEBUG level.
+func (d *Deck) Debug(message ...any) {
+	d.DebugA(message...).With(Depth(1)).Go()
+}
+
+// DebugfA constructs a message according to the format specifier in the default deck at the DEBUG level.
+func DebugfA(format string, message ...any) *Log {
+	return defaultDeck.DebugfA(format, message...)
+}
+
+// Debugf immediately logs a message with no attributes according to the format specifier to the default deck at the DEBUG level.
+func Debugf(format string, message ...any) {
+	defaultDeck.DebugfA(format, message...).With(Depth(1)).Go()
+}
+
+// DebugfA constructs a message according to the format specifier at the DEBUG level.
+func (d *Deck) DebugfA(format string, message ...any) *Log {
+	return newLog(d.logPrintf(DEBUG, format, message...))
+}
+
+// Debugf immediately logs a message with no attributes according to the format specifier at the DEBUG level.
+func (d *Deck) Debugf(format string, message ...any) {
+	d.DebugfA(format, message...).With(Depth(1)).Go()
+}
+
+// DebuglnA constructs a message with a trailing newline in the default deck at the DEBUG level.
+func DebuglnA(message ...any) *Log {
+	return defaultDeck.DebuglnA(message...)
+}
+
+// Debugln immediately logs a message with no attributes and with a trailing newline to the default deck at the DEBUG level.
+func Debugln(message ...any) {
+	defaultDeck.DebuglnA(message...).With(Depth(1)).Go()
+}
+
+// DebuglnA constructs a message with a trailing newline at the DEBUG level.
+func (d *Deck) DebuglnA(message ...any) *Log {
+	return newLog(d.logPrintln(DEBUG, message...))
+}
+
+// Debugln immediately logs a message with no attributes and with a trailing newline at the DEBUG level.
+func (d *Deck) Debugln(message ...any) {
+	d.DebuglnA(message...).With(Depth(1)).Go()
+}
+
 // InfoA constructs a message in the default deck at the INFO level.
 func InfoA(message ...any) *Log {
 	return defaultDeck.InfoA(message...)
@@ -129,7 +687,7 @@ func Info(message ...any) {
 
 // InfoA constructs a message at the INFO level.
 func (d *Deck) InfoA(message ...any) *Log {
-	return d.mkLog(INFO, fmt.Sprint(message...))
+	return newLog(d.logPrint(INFO, message...))
 }
 
 // Info immediately logs a message with no attributes at the INFO level.
@@ -149,7 +707,7 @@ func Infof(format string, message ...any) {
 
 // InfofA constructs a message according to the format specifier at the INFO level.
 func (d *Deck) InfofA(format string, message ...any) *Log {
-	return d.mkLog(INFO, fmt.Sprintf(format, message...))
+	return newLog(d.logPrintf(INFO, format, message...))
 }
 
 // Infof immediately logs a message with no attributes according to the format specifier at the INFO level.
@@ -169,7 +727,7 @@ func Infoln(message ...any) {
 
 // InfolnA constructs a message with a trailing newline at the INFO level.
 func (d *Deck) InfolnA(message ...any) *Log {
-	return d.mkLog(INFO, fmt.Sprintln(message...))
+	return newLog(d.logPrintln(INFO, message...))
 }
 
 // Infoln immediately logs a message with no attributes and with a trailing newline at the INFO level.
@@ -189,7 +747,7 @@ func Error(message ...any) {
 
 // ErrorA constructs a message at the ERROR level.
 func (d *Deck) ErrorA(message ...any) *Log {
-	return d.mkLog(ERROR, fmt.Sprint(message...))
+	return newLog(d.logPrint(ERROR, message...))
 }
 
 // Error immediately logs a message with no attributes at the ERROR level.
@@ -209,7 +767,7 @@ func Errorf(format string, message ...any) {
 
 // ErrorfA constructs a message according to the format specifier at the ERROR level.
 func (d *Deck) ErrorfA(format string, message ...any) *Log {
-	return d.mkLog(ERROR, fmt.Sprintf(format, message...))
+	return newLog(d.logPrintf(ERROR, format, message...))
 }
 
 // Errorf immediately logs a message with no attributes according to the format specifier at the ERROR level.
@@ -229,7 +787,7 @@ func Errorln(message ...any) {
 
 // ErrorlnA constructs a message with a trailing newline at the ERROR level.
 func (d *Deck) ErrorlnA(message ...any) *Log {
-	return d.mkLog(ERROR, fmt.Sprintln(message...))
+	return newLog(d.logPrintln(ERROR, message...))
 }
 
 // Errorln immediately logs a message with no attributes and with a trailing newline at the ERROR level.
@@ -249,7 +807,7 @@ func Warning(message ...any) {
 
 // WarningA constructs a message at the WARNING level.
 func (d *Deck) WarningA(message ...any) *Log {
-	return d.mkLog(WARNING, fmt.Sprint(message...))
+	return newLog(d.logPrint(WARNING, message...))
 }
 
 // Warning immediately logs a message with no attributes at the WARNING level.
@@ -269,7 +827,7 @@ func Warningf(format string, message ...any) {
 
 // WarningfA constructs a message according to the format specifier at the WARNING level.
 func (d *Deck) WarningfA(format string, message ...any) *Log {
-	return d.mkLog(WARNING, fmt.Sprintf(format, message...))
+	return newLog(d.logPrintf(WARNING, format, message...))
 }
 
 // Warningf immediately logs a message with no attributes according to the format specifier at the WARNING level.
@@ -289,12 +847,12 @@ func Warningln(message ...any) {
 
 // WarninglnA constructs a message with a trailing newline at the WARNING level.
 func (d *Deck) WarninglnA(message ...any) *Log {
-	return d.mkLog(WARNING, fmt.Sprintln(message...))
+	return newLog(d.logPrintln(WARNING, message...))
 }
 
 // Warningln immediately logs a message with no attributes and with a trailing newline at the WARNING level.
 func (d *Deck) Warningln(message ...any) {
-	d.WarninglnA(message...).Go()
+	d.WarninglnA(message...).With(Depth(1)).Go()
 }
 
 // FatalA constructs a message in the default deck at the FATAL level.
@@ -309,7 +867,7 @@ func Fatal(message ...any) {
 
 // FatalA constructs a message at the FATAL level.
 func (d *Deck) FatalA(message ...any) *Log {
-	return d.mkLog(FATAL, fmt.Sprint(message...))
+	return newLog(d.logPrint(FATAL, message...))
 }
 
 // Fatal immediately logs a message with no attributes at the FATAL level.
@@ -329,7 +887,7 @@ func Fatalf(format string, message ...any) {
 
 // FatalfA constructs a message according to the format specifier at the FATAL level.
 func (d *Deck) FatalfA(format string, message ...any) *Log {
-	return d.mkLog(FATAL, fmt.Sprintf(format, message...))
+	return newLog(d.logPrintf(FATAL, format, message...))
 }
 
 // Fatalf immediately logs a message with no attributes according to the format specifier at the FATAL level.
@@ -349,7 +907,7 @@ func Fatalln(message ...any) {
 
 // FatallnA constructs a message with a trailing newline at the FATAL level.
 func (d *Deck) FatallnA(message ...any) *Log {
-	return d.mkLog(FATAL, fmt.Sprintln(message...))
+	return newLog(d.logPrintln(FATAL, message...))
 }
 
 // Fatalln immediately logs a message with no attributes and with a trailing newline at the FATAL level.
@@ -357,17 +915,66 @@ func (d *Deck) Fatalln(message ...any) {
 	d.FatallnA(message...).With(Depth(1)).Go()
 }
 
+// Flush flushes the default deck.
+func Flush(ctx context.Context) error {
+	return defaultDeck.Flush(ctx)
+}
+
+// Flush forces buffered output out of the deck, without closing it.
+//
+// Flush first commits any pending summary of repeated messages (see SetDedup), and waits
+// until all messages queued for asynchronous delivery have been written to
+// the backends, or until ctx is done. It then calls Flush on every backend implementing
+// Flusher, followed by Sync on every backend implementing Syncer, and returns their errors
+// joined into a single error.
+func (d *Deck) Flush(ctx context.Context) error {
+	d = d.root()
+	if dd := d.dedup.Load(); dd != nil {
+		dd.flush()
+	}
+	if q := d.async.Load(); q != nil {
+		if err := q.flush(ctx); err != nil {
+			return err
+		}
+	}
+	var errs []error
+	for _, h := range d.load().backends {
+		if !h.acquire() {
+			continue
+		}
+		b := h.backend
+		if f, ok := b.(Flusher); ok {
+			if err := f.Flush(); err != nil {
+				errs = append(errs, fmt.Errorf("flushing %T: %w", b, err))
+			}
+		}
+		if s, ok := b.(Syncer); ok {
+			if err := s.Sync(); err != nil {
+				errs = append(errs, fmt.Errorf("syncing %T: %w", b, err))
+			}
+		}
+		h.release()
+	}
+	return errors.Join(errs...)
+}
+
 // Close closes all backends in the default deck.
 func Close() {
 	defaultDeck.Close()
 }
 
-// Close closes all backends in the deck.
+// Close closes all backends in the deck. Any pending summary of repeated messages, and
+// messages queued for asynchronous delivery, are delivered first.
 func (d *Deck) Close() {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-	for _, b := range d.backends {
-		b.Close()
+	d = d.root()
+	if dd := d.dedup.Load(); dd != nil {
+		dd.flush()
+	}
+	if q := d.async.Load(); q != nil {
+		q.stop()
+	}
+	for _, h := range d.load().backends {
+		h.backend.Close()
 	}
 }
 
@@ -375,74 +982,247 @@ func (d *Deck) Close() {
 //
 // Each log may have one or more attributes associated with it.
 type Log struct {
+	*record
+	gen uint64 // the generation of record which the Log refers to
+}
+
+// newLog returns a Log referring to r. It is small enough to be inlined, so that a Log which
+// is committed by the function creating it needn't be allocated.
+func newLog(r *record) *Log {
+	return &Log{record: r, gen: r.gen.Load()}
+}
+
+// stale reports whether l has been committed. Its record may since hold another message.
+func (l *Log) stale() bool {
+	return l.record.gen.Load() != l.gen
+}
+
+// claim reports whether l may be committed, and makes any later use of l a no-op.
+func (l *Log) claim() bool {
+	return !l.disabled && l.record.gen.CompareAndSwap(l.gen, l.gen+1)
+}
+
+// A record holds a message while it is built and delivered. Records are pooled, so
+// committing a Log advances the record's generation, and the Log no longer refers to it.
+type record struct {
+	gen        atomic.Uint64
+	disabled   bool
+	pooled     bool // returned to recordPool once delivered
+	checked    bool // verbosity was already checked by a Verbose guard
+	summary    bool // a summary of repeated messages, which is never suppressed
+	deck       *Deck
+	level      Level
+	message    string
 	verbosity  int
-	backends   []Composer
-	attributes *AttribStore
+	attributes AttribStore
+	entry      Entry // passed to middleware
 	mu         sync.Mutex
 }
 
+// recordPool holds records for reuse, so that logging a message doesn't allocate one.
+var recordPool = sync.Pool{
+	New: func() any { return &record{pooled: true} },
+}
+
+// release returns l to recordPool once it has been delivered or discarded.
+func (l *record) release() {
+	if !l.pooled {
+		return
+	}
+	l.checked = false
+	l.summary = false
+	l.deck = nil
+	l.message = ""
+	l.attributes.reset()
+	recordPool.Put(l)
+}
+
 // An Attrib is an attribute that can be associated with Logs.
 //
 // Attributes are actually functions which modify values in the AttribStore.
 type Attrib func(*AttribStore)
 
-// An AttribStore stores unique attributes associated with a given Log.
-//
-// The store is a string-keyed value map. Backends can interrogate the store for
-// values, and use the values for their own purposes.
-type AttribStore = sync.Map
-
 // NewLog returns a new Log
 func NewLog(verbosity int) *Log {
-	return &Log{
-		attributes: &AttribStore{},
-		verbosity:  verbosity,
-	}
+	return &Log{record: &record{verbosity: verbosity}}
 }
 
 // With appends one or more attributes to a Log.
 //
 // deck.Info("message with attributes").With(V(2), EventID(3))
 func (l *Log) With(attrs ...Attrib) *Log {
+	if l.disabled || l.stale() {
+		return l
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	for _, o := range attrs {
-		o(l.attributes)
+		o(&l.attributes)
 	}
 	return l
 }
 
-// Go commits a Log to all registered backends in the deck.
+// Go commits a Log to all registered backends in the deck. A Log may only be committed once;
+// using it again afterwards has no effect.
+//
+// Messages at the FATAL level are delivered to every backend, after which the deck is
+// closed and the program terminated via the deck's exit function.
+//
+// Errors returned by the backends are passed to the deck's error handler, if any.
 func (l *Log) Go() {
+	if l.claim() {
+		l.dispatch()
+	}
+}
+
+// GoErr commits a Log to all registered backends in the deck, and returns the errors
+// returned by the backends joined into a single error. A Log may only be committed once;
+// using it again afterwards has no effect.
+//
+// Errors are also passed to the deck's error handler, if any.
+func (l *Log) GoErr() error {
+	if !l.claim() {
+		return nil
+	}
+	return l.dispatch()
+}
+
+func (l *record) dispatch() error {
+	if l.disabled {
+		return nil
+	}
+	if l.level == FATAL && l.deck != nil {
+		defer l.deck.fatal()
+	}
+
+	i := 0
+	if v, ok := VerbosityKey.Get(&l.attributes); ok {
+		i = v
+	}
+	// FATAL messages terminate the program, so they are never discarded for their verbosity.
+	if i > l.verbosity && !l.checked && l.level < FATAL {
+		// The caller is only looked up when the message would otherwise be discarded.
+		if l.deck == nil {
+			return nil
+		}
+		var vl int
+		if c := l.attributes.caller; c != nil {
+			vl = l.deck.vmoduleLevelAt(c.PC)
+		} else {
+			depth, _ := DepthKey.Get(&l.attributes)
+			vl = l.deck.vmoduleLevel(depth + 2)
+		}
+		if i > vl {
+			l.release()
+			return nil
+		}
+	}
+
+	if l.deck == nil {
+		return nil
+	}
+	c := l.deck.load()
+	l.capture(c.goroutines)
+
+	if s, ok := samplerKey.Get(&l.attributes); ok && l.level < FATAL && !l.summary {
+		name, _ := sampleNameKey.Get(&l.attributes)
+		var site uintptr
+		if name == "" && l.attributes.caller != nil {
+			site = l.attributes.caller.PC
+		}
+		if !l.deck.sample(s, name, site) {
+			l.release()
+			return nil
+		}
+	}
+
+	// A summary carries the attributes of a message which has already been through the
+	// middleware and the redactor.
+	if len(c.middleware) > 0 && !l.summary && !l.filter(c.middleware) {
+		l.release()
+		return nil
+	}
+	if c.redactor != nil && !l.summary {
+		c.redactor.redactLog(l)
+	}
+
+	if l.level < FATAL && !l.summary {
+		if dd := l.deck.dedup.Load(); dd != nil && dd.repeated(l) {
+			l.release()
+			return nil
+		}
+	}
+
+	// Messages logged by the error handler are delivered synchronously, as the handler may be
+	// running on a worker which would otherwise wait on its own queue.
+	if q := l.deck.async.Load(); q != nil && !l.deck.inErrorHandler() {
+		if l.level == FATAL {
+			q.flush(context.Background())
+		} else if q.enqueue(l) {
+			return nil
+		}
+	}
+	err := l.deliver()
+	l.release()
+	return err
+}
+
+// deliver composes and writes l to each of the deck's backends.
+func (l *record) deliver() error {
+	if l.deck == nil {
+		return nil
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	for _, o := range l.backends {
-		i := 0
-		if lvl, ok := l.attributes.Load("Verbosity"); ok {
-			i = lvl.(int)
+	v, _ := VerbosityKey.Get(&l.attributes)
+	var errs []error
+	for _, h := range l.deck.load().backends {
+		// A snapshot loaded before Replace may hold a retired Handle.
+		if h = h.current(); h == nil {
+			continue
+		}
+		if !h.accepts(l.level, v) {
+			h.release()
+			continue
 		}
-		if i <= l.verbosity {
-			o.Compose(l.attributes)
-			o.Write()
+		o := h.backend.New(l.level, l.message)
+		cerr := o.Compose(&l.attributes)
+		werr := o.Write()
+		// Errors are reported once h is released, as the error handler may remove or replace
+		// the backend, which waits for writes in progress.
+		h.release()
+		if cerr != nil {
+			errs = append(errs, l.failed(h.backend, cerr))
+		}
+		if werr != nil {
+			errs = append(errs, l.failed(h.backend, werr))
 		}
 	}
+	return errors.Join(errs...)
+}
+
+// failed reports err to the deck's error handler and returns it.
+func (l *record) failed(b Backend, err error) error {
+	l.deck.handleError(b, l.level, err)
+	return err
 }
 
 // Depth is a general attribute that allows specifying log depth to backends. Depth
 // may be used to modify log rendering under certain circumstances.
 func Depth(d int) func(*AttribStore) {
 	return func(a *AttribStore) {
-		a.Store("Depth", d)
+		DepthKey.Set(a, d)
 	}
 }
 
-// V is a special attribute that sets the verbosity level on a message.
+// V is a special attribute that sets the verbosity level on a message. FATAL messages are
+// delivered regardless of their verbosity.
 //
 // deck.Info("example with verbosity 2").V(2).Go()
 func V(v int) func(*AttribStore) {
 	return func(a *AttribStore) {
-		a.Store("Verbosity", v)
+		VerbosityKey.Set(a, v)
 	}
 }
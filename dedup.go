@@ -0,0 +1,175 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// SetDedup configures duplicate suppression for the default deck.
+func SetDedup(window time.Duration) {
+	defaultDeck.SetDedup(window)
+}
+
+// SetDedup collapses consecutive identical messages, in the style of syslogd.
+//
+// Once a message has been committed, identical messages which follow it are counted rather
+// than committed. Messages are identical if they have the same level, text, fields and other
+// attributes, ignoring Depth and the captured time and caller. When a different message is
+// logged, or window has passed since the first repeat, a summary such as "last message
+// repeated 3 times" is committed with the level, attributes and fields of the repeated
+// message, as they were after any Middleware and redaction; the summary is not passed through
+// them again. Flush and Close also commit any pending summary.
+//
+// FATAL messages are never suppressed. A window of zero disables duplicate suppression,
+// which is the default.
+func (d *Deck) SetDedup(window time.Duration) {
+	d = d.root()
+	var dd *dedup
+	if window > 0 {
+		dd = &dedup{deck: d, window: window}
+	}
+	if old := d.dedup.Swap(dd); old != nil {
+		old.flush()
+	}
+}
+
+type dedup struct {
+	deck   *Deck
+	window time.Duration
+
+	mu    sync.Mutex
+	last  dedupEntry
+	seen  bool
+	count int
+	timer *time.Timer
+}
+
+// dedupEntry records the identity of the last committed message.
+type dedupEntry struct {
+	level   Level
+	message string
+	caller  *Caller
+	attrs   []attrib
+	fields  []KeyValue
+}
+
+// repeated reports whether l repeats the last committed message, in which case it is counted
+// and should be dropped. Otherwise l becomes the last committed message, and the summary of
+// any repeats of the previous one is committed first.
+func (dd *dedup) repeated(l *record) bool {
+	dd.mu.Lock()
+	if dd.seen && dd.last.matches(l) {
+		dd.count++
+		if dd.timer == nil {
+			dd.timer = time.AfterFunc(dd.window, dd.flush)
+		}
+		dd.mu.Unlock()
+		return true
+	}
+	s := dd.reset()
+	dd.last.record(l)
+	dd.seen = true
+	dd.mu.Unlock()
+
+	if s != nil {
+		s.dispatch()
+	}
+	return false
+}
+
+// flush commits the summary of any repeats counted so far.
+func (dd *dedup) flush() {
+	dd.mu.Lock()
+	s := dd.reset()
+	dd.mu.Unlock()
+	if s != nil {
+		s.dispatch()
+	}
+}
+
+// reset clears the count of repeats, returning the summary of those counted so far, or nil if
+// there were none. dd.mu must be held.
+func (dd *dedup) reset() *record {
+	if dd.timer != nil {
+		dd.timer.Stop()
+		dd.timer = nil
+	}
+	n := dd.count
+	dd.count = 0
+	if n == 0 || !dd.deck.enabled(dd.last.level) {
+		return nil
+	}
+	return dd.last.summary(dd.deck, n)
+}
+
+// summary returns a message summarizing n repeats of e. It is attributed to the caller of the
+// repeated message and has the same attributes and fields, so that it passes the same
+// verbosity checks and reaches the same backends.
+func (e *dedupEntry) summary(d *Deck, n int) *record {
+	l := d.newRecord(e.level, fmt.Sprintf("last message repeated %d times", n))
+	for _, a := range e.attrs {
+		l.attributes.store(a.key, a.value)
+	}
+	l.attributes.fields = append(l.attributes.fields, e.fields...)
+	l.attributes.caller = e.caller
+	l.summary = true
+	return l
+}
+
+func (e *dedupEntry) record(l *record) {
+	e.level = l.level
+	e.message = l.message
+	e.caller = l.attributes.caller
+	e.attrs = e.attrs[:0]
+	for i := 0; i < l.attributes.n; i++ {
+		if a := l.attributes.at(i); !ignoredByDedup(a.key) {
+			e.attrs = append(e.attrs, *a)
+		}
+	}
+	e.fields = append(e.fields[:0], l.attributes.fields...)
+}
+
+func (e *dedupEntry) matches(l *record) bool {
+	if l.level != e.level || l.message != e.message || len(l.attributes.fields) != len(e.fields) {
+		return false
+	}
+	for i, f := range l.attributes.fields {
+		if f.Key != e.fields[i].Key || !reflect.DeepEqual(f.Value, e.fields[i].Value) {
+			return false
+		}
+	}
+	n := 0
+	for i := 0; i < l.attributes.n; i++ {
+		a := l.attributes.at(i)
+		if ignoredByDedup(a.key) {
+			continue
+		}
+		if n >= len(e.attrs) || e.attrs[n].key != a.key || !reflect.DeepEqual(e.attrs[n].value, a.value) {
+			return false
+		}
+		n++
+	}
+	return n == len(e.attrs)
+}
+
+// ignoredByDedup reports whether the attribute stored under key differs between otherwise
+// identical messages. The captured time, caller and goroutine are not compared either.
+func ignoredByDedup(key any) bool {
+	return key == any(DepthKey)
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// A StackTracer is an error which records the stack where it was created, such as the errors
+// of github.com/go-errors/errors. Callers returns program counters as returned by
+// runtime.Callers.
+type StackTracer interface {
+	error
+	Callers() []uintptr
+}
+
+// An ErrorNode describes an error and the errors it wraps.
+type ErrorNode struct {
+	Err error
+	// Stack is the stack recorded by Err, if it is a StackTracer.
+	Stack []uintptr
+	// Wrapped holds the errors returned by the Unwrap method of Err, which may return a single
+	// error or, as for errors.Join, a slice of errors.
+	Wrapped []ErrorNode
+}
+
+// maxErrorDepth limits how deeply ErrorTree follows wrapped errors, in case an error wraps
+// itself.
+const maxErrorDepth = 64
+
+// ErrorTree returns the tree of errors wrapped by err.
+func ErrorTree(err error) ErrorNode {
+	return errorTree(err, 0)
+}
+
+func errorTree(err error, depth int) ErrorNode {
+	n := ErrorNode{Err: err}
+	if st, ok := err.(StackTracer); ok {
+		n.Stack = st.Callers()
+	}
+	if depth >= maxErrorDepth {
+		return n
+	}
+	var wrapped []error
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		wrapped = e.Unwrap()
+	default:
+		if w := errors.Unwrap(err); w != nil {
+			wrapped = []error{w}
+		}
+	}
+	for _, w := range wrapped {
+		if w != nil {
+			n.Wrapped = append(n.Wrapped, errorTree(w, depth+1))
+		}
+	}
+	return n
+}
+
+// hasStack reports whether any error in the tree records a stack.
+func (n ErrorNode) hasStack() bool {
+	if len(n.Stack) > 0 {
+		return true
+	}
+	for _, w := range n.Wrapped {
+		if w.hasStack() {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatError renders the tree of errors wrapped by err, one error per line, indented by how
+// deeply it is wrapped. The stack recorded by a StackTracer follows its error.
+//
+//	load config: open app.conf: permission denied
+//	  open app.conf: permission denied
+//	    permission denied
+func FormatError(err error) string {
+	return string(appendErrorNode(nil, ErrorTree(err), ""))
+}
+
+func appendErrorNode(buf []byte, n ErrorNode, indent string) []byte {
+	buf = append(buf, indent...)
+	buf = append(buf, strings.ReplaceAll(n.Err.Error(), "\n", "; ")...)
+	buf = append(buf, '\n')
+	buf = appendStack(buf, n.Stack, indent+"  ")
+	for _, w := range n.Wrapped {
+		buf = appendErrorNode(buf, w, indent+"  ")
+	}
+	return buf
+}
+
+// StackKey holds the stack captured by the Stack attribute.
+var StackKey = NewKey[[]uintptr]("Stack")
+
+// Stack is an attribute which records the stack of the goroutine which logs the message.
+// The stack is captured when the message is committed by Go or GoErr, not when Stack is
+// applied, and starts at the caller of Go or GoErr, skipping as many further frames as set
+// by Depth. Backends find it under StackKey, and may render it with FormatStack.
+//
+//	deck.ErrorA("unexpected state").With(deck.Stack()).Go()
+func Stack() func(*AttribStore) {
+	return func(a *AttribStore) {
+		// The stack is captured when the message is committed; a nil stack requests it.
+		StackKey.Set(a, nil)
+	}
+}
+
+// stack returns the stack skip frames above the caller of stack.
+func stack(skip int) []uintptr {
+	pcs := make([]uintptr, 32)
+	for {
+		if n := runtime.Callers(skip+2, pcs); n < len(pcs) {
+			return pcs[:n]
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+}
+
+// FormatStack renders a stack of program counters as returned by runtime.Callers, with each
+// function followed by its file and line number on an indented line, in the style of
+// runtime/debug.Stack.
+//
+//	main.main
+//		/src/app/main.go:12
+func FormatStack(pcs []uintptr) string {
+	return string(appendStack(nil, pcs, ""))
+}
+
+func appendStack(buf []byte, pcs []uintptr, indent string) []byte {
+	if len(pcs) == 0 {
+		return buf
+	}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		buf = append(buf, indent...)
+		buf = append(buf, f.Function...)
+		buf = append(buf, '\n')
+		buf = append(buf, indent...)
+		buf = append(buf, '\t')
+		buf = append(buf, f.File...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(f.Line), 10)
+		buf = append(buf, '\n')
+		if !more {
+			return buf
+		}
+	}
+}
+
+// AppendDetails appends the details of a message which don't fit on a single line to buf, and
+// returns the extended buffer: the stack captured by Stack, and the tree of each error field
+// which records a stack. Backends which write text may append these after the message.
+func AppendDetails(buf []byte, a *AttribStore) []byte {
+	if pcs, ok := StackKey.Get(a); ok {
+		buf = appendStack(buf, pcs, "")
+	}
+	for _, f := range a.fields {
+		err, ok := f.Value.(error)
+		if !ok {
+			continue
+		}
+		if n := ErrorTree(err); n.hasStack() {
+			buf = append(buf, f.Key...)
+			buf = append(buf, ":\n"...)
+			buf = appendErrorNode(buf, n, "  ")
+		}
+	}
+	return buf
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package execdeck logs the output of child processes to a deck.
+//
+// Each line a command writes to its stdout or stderr becomes a message, so the output of
+// external tools reaches the same backends as the program's own messages:
+//
+//	cmd := exec.Command("fsck", "-n", "/dev/sda1")
+//	err := execdeck.Run(d, cmd, execdeck.Classify(regexp.MustCompile(`^(?i)error:`), deck.ERROR))
+//
+// Messages carry the fields "command", the base name of the command, "pid", its process ID,
+// and "stream", either "stdout" or "stderr". Partial lines are buffered until they are
+// complete, and lines longer than 64 KiB are split into several messages.
+package execdeck
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+
+	"github.com/google/deck"
+)
+
+// An Option configures how the output of a command is logged.
+type Option func(*options)
+
+type options struct {
+	stdout, stderr deck.Level
+	rules          []rule
+	attrs          []deck.Attrib
+}
+
+type rule struct {
+	re    *regexp.Regexp
+	level deck.Level
+}
+
+// StdoutLevel sets the level of lines written to stdout, which is INFO by default.
+func StdoutLevel(lvl deck.Level) Option {
+	return func(o *options) {
+		o.stdout = lvl
+	}
+}
+
+// StderrLevel sets the level of lines written to stderr, which is WARNING by default.
+func StderrLevel(lvl deck.Level) Option {
+	return func(o *options) {
+		o.stderr = lvl
+	}
+}
+
+// Classify logs lines matching re, on either stream, at lvl rather than the level of their
+// stream. Rules are tried in the order they were given, and the first match decides the
+// level.
+func Classify(re *regexp.Regexp, lvl deck.Level) Option {
+	return func(o *options) {
+		o.rules = append(o.rules, rule{re: re, level: lvl})
+	}
+}
+
+// Attrs adds attributes to every message logged for the command.
+func Attrs(attrs ...deck.Attrib) Option {
+	return func(o *options) {
+		o.attrs = append(o.attrs, attrs...)
+	}
+}
+
+// Capture sets the Stdout and Stderr of cmd, which must not already be set, so that its
+// output is logged to d, or to the default deck if d is nil. Capture must be called before
+// cmd is started.
+//
+// The returned function logs any output after the final newline of each stream. It should be
+// called once cmd.Wait has returned.
+func Capture(d *deck.Deck, cmd *exec.Cmd, opts ...Option) (done func(), err error) {
+	if cmd.Stdout != nil || cmd.Stderr != nil {
+		return nil, errors.New("execdeck: Stdout or Stderr already set")
+	}
+	o := options{stdout: deck.INFO, stderr: deck.WARNING}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	name := filepath.Base(cmd.Path)
+	pid := func(a *deck.AttribStore) {
+		// The process is started before any output is written.
+		if cmd.Process != nil {
+			deck.Int("pid", cmd.Process.Pid)(a)
+		}
+	}
+	writer := func(stream string, lvl deck.Level) *deck.Writer {
+		attrs := append([]deck.Attrib{deck.Str("command", name), pid, deck.Str("stream", stream)}, o.attrs...)
+		w := deck.NewWriter(d, lvl, attrs...)
+		for _, r := range o.rules {
+			w.Classify(r.re, r.level)
+		}
+		return w
+	}
+	stdout, stderr := writer("stdout", o.stdout), writer("stderr", o.stderr)
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	return func() {
+		stdout.Close()
+		stderr.Close()
+	}, nil
+}
+
+// Run runs cmd with its output logged to d as by Capture, and waits for it to complete.
+func Run(d *deck.Deck, cmd *exec.Cmd, opts ...Option) error {
+	done, err := Capture(d, cmd, opts...)
+	if err != nil {
+		return err
+	}
+	defer done()
+	return cmd.Run()
+}
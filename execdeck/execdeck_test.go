@@ -0,0 +1,99 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package execdeck
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/deck"
+	"github.com/google/deck/backends/replay"
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestHelperProcess is run as the child process by the other tests.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("EXECDECK_HELPER") != "1" {
+		return
+	}
+	fmt.Fprint(os.Stdout, "starting\nerror: disk ")
+	fmt.Fprint(os.Stderr, "progress 50%\r\n")
+	fmt.Fprint(os.Stdout, "full\n"+strings.Repeat("x", 70<<10)+"\nunterminated")
+	os.Exit(0)
+}
+
+func helper() *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), "EXECDECK_HELPER=1")
+	return cmd
+}
+
+func TestRun(t *testing.T) {
+	d := deck.New()
+	r := replay.Init()
+	d.Add(r)
+	cmd := helper()
+	err := Run(d, cmd, StderrLevel(deck.DEBUG), Classify(regexp.MustCompile(`^error:`), deck.ERROR), Attrs(deck.Str("job", "test")))
+	if err != nil {
+		t.Fatalf("Run(): produced unexpected error: %v", err)
+	}
+
+	fields := func(stream string) []deck.KeyValue {
+		return []deck.KeyValue{
+			{Key: "command", Value: filepath.Base(cmd.Path)},
+			{Key: "pid", Value: cmd.Process.Pid},
+			{Key: "stream", Value: stream},
+			{Key: "job", Value: "test"},
+		}
+	}
+	long := strings.Repeat("x", 70<<10)
+	want := replay.Bundle{
+		{Level: deck.INFO, Message: "starting", Fields: fields("stdout")},
+		{Level: deck.DEBUG, Message: "progress 50%", Fields: fields("stderr")},
+		{Level: deck.ERROR, Message: "error: disk full", Fields: fields("stdout")},
+		{Level: deck.INFO, Message: long[:64<<10], Fields: fields("stdout")},
+		{Level: deck.INFO, Message: long[64<<10:], Fields: fields("stdout")},
+		{Level: deck.INFO, Message: "unterminated", Fields: fields("stdout")},
+	}
+	got := r.All()
+	// The streams are copied concurrently, so only the order within each stream is fixed.
+	byStream := func(b replay.Bundle, stream string) replay.Bundle {
+		var out replay.Bundle
+		for _, l := range b {
+			if l.Fields[2].Value == stream {
+				out = append(out, l)
+			}
+		}
+		return out
+	}
+	for _, stream := range []string{"stdout", "stderr"} {
+		if diff := cmp.Diff(byStream(got, stream), byStream(want, stream)); diff != "" {
+			t.Errorf("Run(): produced unexpected diff for %s: %s", stream, diff)
+		}
+	}
+}
+
+func TestCaptureSet(t *testing.T) {
+	cmd := helper()
+	cmd.Stdout = os.Stdout
+	if _, err := Capture(deck.New(), cmd); err == nil {
+		t.Errorf("Capture(): produced unexpected success with Stdout already set")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"unicode"
+)
+
+// A KeyValue is a structured field attached to a message with Field.
+type KeyValue struct {
+	Key   string
+	Value any
+}
+
+// Field is an attribute that attaches a structured key/value field to a message.
+//
+// Fields are kept in the order they were added. Adding a field with a key that is already
+// present replaces the earlier value in place. Backends retrieve fields with Fields.
+//
+// deck.InfoA("request served").With(deck.Field("path", path), deck.Dur("latency", d)).Go()
+func Field(key string, value any) func(*AttribStore) {
+	return func(a *AttribStore) {
+		for i, f := range a.fields {
+			if f.Key == key {
+				a.fields[i].Value = value
+				return
+			}
+		}
+		a.fields = append(a.fields, KeyValue{Key: key, Value: value})
+	}
+}
+
+// Str is a Field holding a string value.
+func Str(key, value string) func(*AttribStore) {
+	return Field(key, value)
+}
+
+// Int is a Field holding an int value.
+func Int(key string, value int) func(*AttribStore) {
+	return Field(key, value)
+}
+
+// Bool is a Field holding a bool value.
+func Bool(key string, value bool) func(*AttribStore) {
+	return Field(key, value)
+}
+
+// Float is a Field holding a float64 value.
+func Float(key string, value float64) func(*AttribStore) {
+	return Field(key, value)
+}
+
+// Dur is a Field holding a time.Duration value.
+func Dur(key string, value time.Duration) func(*AttribStore) {
+	return Field(key, value)
+}
+
+// Time is a Field holding a time.Time value.
+func Time(key string, value time.Time) func(*AttribStore) {
+	return Field(key, value)
+}
+
+// Err is a Field holding an error under the "error" key. The original error value is kept, so
+// backends may inspect it with errors.Is and errors.As, and render the errors it wraps and any
+// stack they record with ErrorTree or FormatError.
+func Err(err error) func(*AttribStore) {
+	return Field("error", err)
+}
+
+// Fields returns the fields attached to a message, in the order they were added.
+//
+// The returned slice belongs to the message, and is reused once the message has been
+// written. Backends which keep fields beyond Write must copy them.
+func Fields(a *AttribStore) []KeyValue {
+	if len(a.fields) == 0 {
+		return nil
+	}
+	return a.fields
+}
+
+// FormatFields renders fields as space separated key=value pairs. Values are formatted with
+// fmt.Sprint, and quoted if they are empty or contain spaces, quotes, '=' or unprintable
+// characters.
+func FormatFields(fields []KeyValue) string {
+	return string(AppendFields(nil, fields))
+}
+
+// AppendFields appends fields to buf as formatted by FormatFields, and returns the extended
+// buffer. Backends can use it with a reused buffer to format fields without allocating.
+func AppendFields(buf []byte, fields []KeyValue) []byte {
+	for i, f := range fields {
+		if i > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = append(buf, f.Key...)
+		buf = append(buf, '=')
+		switch v := f.Value.(type) {
+		case string:
+			buf = appendQuoted(buf, v)
+		case int:
+			buf = strconv.AppendInt(buf, int64(v), 10)
+		case int64:
+			buf = strconv.AppendInt(buf, v, 10)
+		case bool:
+			buf = strconv.AppendBool(buf, v)
+		default:
+			start := len(buf)
+			buf = fmt.Append(buf, v)
+			if needsQuote(string(buf[start:])) {
+				buf = appendQuoted(buf[:start], string(buf[start:]))
+			}
+		}
+	}
+	return buf
+}
+
+func appendQuoted(buf []byte, s string) []byte {
+	if needsQuote(s) {
+		return strconv.AppendQuote(buf, s)
+	}
+	return append(buf, s...)
+}
+
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+// An Entry is a message on its way to the backends, as seen by Middleware.
+type Entry struct {
+	Level      Level
+	Message    string
+	Attributes *AttribStore
+}
+
+// Middleware inspects a message before it reaches the backends. It may modify the level,
+// text or attributes of the message, and returns false to drop it.
+//
+// The Entry and its AttribStore are only valid until the middleware returns.
+type Middleware func(e *Entry) bool
+
+// Use adds middleware to the default deck.
+func Use(mw ...Middleware) {
+	defaultDeck.Use(mw...)
+}
+
+// Use adds middleware to the deck, which runs in the order it was added for every message
+// committed by the deck or any of its children.
+//
+// Middleware runs on the goroutine which commits the message, after the verbosity and
+// sampling checks and before duplicate suppression, asynchronous delivery or any call to a
+// backend. Dropping a FATAL message still terminates the program.
+//
+//	d.Use(func(e *deck.Entry) bool {
+//		deck.Str("host", hostname)(e.Attributes)
+//		return !strings.Contains(e.Message, "/healthz")
+//	})
+func (d *Deck) Use(mw ...Middleware) {
+	d.root().update(func(c *config) {
+		c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], mw...)
+	})
+}
+
+// filter runs the deck's middleware on l, and reports whether l should be committed.
+func (l *record) filter(mw []Middleware) bool {
+	e := &l.entry
+	*e = Entry{Level: l.level, Message: l.message, Attributes: &l.attributes}
+	defer func() { *e = Entry{} }()
+	for _, m := range mw {
+		if !m(e) {
+			return false
+		}
+	}
+	l.level, l.message = e.Level, e.Message
+	return true
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"context"
+	"runtime"
+	"strings"
+)
+
+// A RecoverOption configures how Recover and Go log a panic.
+type RecoverOption func(*recoverOptions)
+
+type recoverOptions struct {
+	level   Level
+	repanic bool
+	attrs   []Attrib
+}
+
+// PanicLevel sets the level at which a panic is logged, which is ERROR by default. A panic
+// logged at FATAL terminates the program through the deck's exit function.
+func PanicLevel(lvl Level) RecoverOption {
+	return func(o *recoverOptions) {
+		o.level = lvl
+	}
+}
+
+// Repanic makes Recover panic again with the recovered value, once the panic has been logged
+// and the backends flushed.
+func Repanic() RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = true
+	}
+}
+
+// PanicAttrs adds attributes to the message logged for a panic.
+func PanicAttrs(attrs ...Attrib) RecoverOption {
+	return func(o *recoverOptions) {
+		o.attrs = append(o.attrs, attrs...)
+	}
+}
+
+// Recover logs a panic in the calling goroutine to d, or to the default deck if d is nil. It
+// must be deferred directly:
+//
+//	defer deck.Recover(d)
+//
+// The message holds the panic value, under the "panic" field or as Err if it is an error, and
+// the stack of the panicking goroutine under StackKey. Its caller is the code which panicked.
+// The backends are flushed before Recover returns, so the message is not lost if the program
+// then exits.
+func Recover(d *Deck, opts ...RecoverOption) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if d == nil {
+		d = defaultDeck
+	}
+	o := recoverOptions{level: ERROR}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	l := newLog(d.logPrintf(o.level, "panic: %v", r))
+	if !l.disabled {
+		pcs := panicStack(stack(1))
+		if len(pcs) > 0 {
+			l.attributes.caller = callerAt(pcs[0])
+		}
+		StackKey.Set(&l.attributes, pcs)
+		if err, ok := r.(error); ok {
+			Err(err)(&l.attributes)
+		} else {
+			Field("panic", r)(&l.attributes)
+		}
+		l.With(o.attrs...).Go()
+	}
+	d.Flush(context.Background())
+	if o.repanic {
+		panic(r)
+	}
+}
+
+// panicStack trims the frames of Recover and the runtime's panic handling from pcs, so it
+// starts at the code which panicked.
+func panicStack(pcs []uintptr) []uintptr {
+	panicking := false
+	for i := range pcs {
+		f, _ := runtime.CallersFrames(pcs[i : i+1]).Next()
+		if panicking && !strings.HasPrefix(f.Function, "runtime.") {
+			return pcs[i:]
+		}
+		if f.Function == "runtime.gopanic" {
+			panicking = true
+		}
+	}
+	return pcs
+}
+
+// Go runs f in a new goroutine, logging any panic in f to the default deck as Recover does.
+func Go(f func(), opts ...RecoverOption) {
+	defaultDeck.Go(f, opts...)
+}
+
+// Go runs f in a new goroutine, logging any panic in f to the deck as Recover does.
+//
+//	d.Go(func() { serve(conn) }, deck.Repanic())
+func (d *Deck) Go(f func(), opts ...RecoverOption) {
+	go func() {
+		defer Recover(d, opts...)
+		f()
+	}()
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A sampler is a policy deciding which messages from a call site are committed.
+type sampler struct {
+	every int     // commit one message in every n
+	first int     // commit the first n messages
+	rate  float64 // commit rate messages per second, after an initial burst
+	burst int
+}
+
+// The sampling keys are stored in slots which are not listed in slotKeys, so that backends
+// never see them.
+var (
+	samplerKey = &Key[sampler]{name: "Sampler", slot: &slot[sampler]{
+		get: func(s *AttribStore) (sampler, bool) { return s.sampler, s.hasSampler },
+		set: func(s *AttribStore, v sampler) { s.sampler, s.hasSampler = v, true },
+		del: func(s *AttribStore) { s.sampler, s.hasSampler = sampler{}, false },
+	}}
+	sampleNameKey = &Key[string]{name: "SampleKey", slot: &slot[string]{
+		get: func(s *AttribStore) (string, bool) { return s.sampleKey, s.sampleKey != "" },
+		set: func(s *AttribStore, k string) { s.sampleKey = k },
+		del: func(s *AttribStore) { s.sampleKey = "" },
+	}}
+)
+
+// Every is an attribute which commits only the first of every n messages logged from the
+// same call site, or with the same SampleKey.
+//
+//	deck.ErrorA("query failed:", err).With(deck.Every(100)).Go()
+//
+// Sampling attributes apply to every message except FATAL messages, and are checked before
+// the message reaches any backend. Suppressed messages are counted; see Deck.Suppressed.
+func Every(n int) func(*AttribStore) {
+	return func(a *AttribStore) {
+		samplerKey.Set(a, sampler{every: max(n, 1)})
+	}
+}
+
+// FirstN is an attribute which commits only the first n messages logged from the same call
+// site, or with the same SampleKey.
+func FirstN(n int) func(*AttribStore) {
+	return func(a *AttribStore) {
+		samplerKey.Set(a, sampler{first: max(n, 0)})
+	}
+}
+
+// Once is an attribute which commits only the first message logged from the same call site,
+// or with the same SampleKey.
+func Once() func(*AttribStore) {
+	return FirstN(1)
+}
+
+// PerSecond is an attribute which commits at most rate messages per second from the same
+// call site, or with the same SampleKey, after allowing an initial burst of messages. A rate
+// of zero or less commits only the initial burst.
+func PerSecond(rate float64, burst int) func(*AttribStore) {
+	burst = max(burst, 1)
+	if !(rate > 0) {
+		return FirstN(burst)
+	}
+	return func(a *AttribStore) {
+		samplerKey.Set(a, sampler{rate: rate, burst: burst})
+	}
+}
+
+// SampleKey is an attribute which makes sampling attributes share their state with every
+// other message sampled under the same key, rather than with messages from the same call
+// site.
+//
+// The deck keeps the state of every key for as long as the deck exists, so keys should come
+// from a small, fixed set, such as the names of a program's dependencies, and never from
+// values such as user IDs or request paths.
+//
+//	deck.ErrorA("backend unavailable").With(deck.SampleKey("backend"), deck.PerSecond(1, 5)).Go()
+func SampleKey(key string) func(*AttribStore) {
+	return func(a *AttribStore) {
+		sampleNameKey.Set(a, key)
+	}
+}
+
+// sampleID identifies the state shared by sampled messages.
+type sampleID struct {
+	site    uintptr // the call site, if no SampleKey was given
+	name    string
+	sampler sampler
+}
+
+type sampleState struct {
+	count      atomic.Uint64
+	suppressed atomic.Uint64
+
+	mu     sync.Mutex // guards tokens and last, for PerSecond
+	tokens float64
+	last   time.Time
+}
+
+// sample reports whether a message with sampler s should be committed.
+func (d *Deck) sample(s sampler, name string, site uintptr) bool {
+	id := sampleID{site: site, name: name, sampler: s}
+	v, ok := d.samples.Load(id)
+	if !ok {
+		v, _ = d.samples.LoadOrStore(id, &sampleState{tokens: float64(s.burst)})
+	}
+	st := v.(*sampleState)
+	if !st.allow(s) {
+		st.suppressed.Add(1)
+		d.suppressed.Add(1)
+		return false
+	}
+	return true
+}
+
+func (st *sampleState) allow(s sampler) bool {
+	switch {
+	case s.every > 0:
+		return (st.count.Add(1)-1)%uint64(s.every) == 0
+	case s.rate > 0:
+		st.mu.Lock()
+		defer st.mu.Unlock()
+		now := time.Now()
+		if !st.last.IsZero() {
+			st.tokens = min(st.tokens+now.Sub(st.last).Seconds()*s.rate, float64(s.burst))
+		}
+		st.last = now
+		if st.tokens < 1 {
+			return false
+		}
+		st.tokens--
+		return true
+	default:
+		return st.count.Add(1) <= uint64(s.first)
+	}
+}
+
+// Suppressed returns the number of messages suppressed by sampling attributes in the default
+// deck.
+func Suppressed() uint64 {
+	return defaultDeck.Suppressed()
+}
+
+// Suppressed returns the number of messages suppressed by sampling attributes such as Every
+// and PerSecond.
+func (d *Deck) Suppressed() uint64 {
+	return d.root().suppressed.Load()
+}
+
+// SuppressedByKey returns the number of messages suppressed by sampling attributes in the
+// default deck, by SampleKey or call site.
+func SuppressedByKey() map[string]uint64 {
+	return defaultDeck.SuppressedByKey()
+}
+
+// SuppressedByKey returns the number of messages suppressed by sampling attributes, indexed
+// by SampleKey, or by "file:line" for messages sampled by call site.
+func (d *Deck) SuppressedByKey() map[string]uint64 {
+	counts := map[string]uint64{}
+	d.root().samples.Range(func(k, v any) bool {
+		id := k.(sampleID)
+		name := id.name
+		if name == "" {
+			f, _ := runtime.CallersFrames([]uintptr{id.site}).Next()
+			name = fmt.Sprintf("%s:%d", filepath.Base(f.File), f.Line)
+		}
+		counts[name] += v.(*sampleState).suppressed.Load()
+		return true
+	})
+	return counts
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slogdeck provides a log/slog Handler which writes to a deck.
+//
+// This allows code that logs through slog.Logger to share the backends of an existing deck:
+//
+//	logger := slog.New(slogdeck.NewHandler(deck.Default()))
+//	logger.Info("hello from slog", "user", "alice")
+//
+// slog levels are mapped onto the nearest deck level at or below them. slog attributes are
+// passed to the deck as fields (see deck.Field) in the order they were added, with the keys of
+// grouped attributes qualified by their group names and joined with a "."
+package slogdeck
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/google/deck"
+)
+
+// Handler is a slog.Handler which writes records to a deck.
+type Handler struct {
+	deck   *deck.Deck
+	attrs  []deck.KeyValue // attributes added with WithAttrs, already qualified and resolved
+	prefix string          // group prefix added with WithGroup
+}
+
+// NewHandler returns a Handler which writes to d. If d is nil, the default deck is used.
+func NewHandler(d *deck.Deck) *Handler {
+	if d == nil {
+		d = deck.Default()
+	}
+	return &Handler{deck: d}
+}
+
+// Level maps a slog level onto a deck level.
+//
+// Levels below slog.LevelInfo map to DEBUG, levels below slog.LevelWarn map to INFO, levels
+// below slog.LevelError map to WARNING and everything else maps to ERROR. Records are never
+// mapped to FATAL, as deck terminates the program after FATAL messages.
+func Level(l slog.Level) deck.Level {
+	switch {
+	case l < slog.LevelInfo:
+		return deck.DEBUG
+	case l < slog.LevelWarn:
+		return deck.INFO
+	case l < slog.LevelError:
+		return deck.WARNING
+	default:
+		return deck.ERROR
+	}
+}
+
+// Enabled reports whether the deck accepts messages at level l.
+func (h *Handler) Enabled(ctx context.Context, l slog.Level) bool {
+	return Level(l) >= h.deck.Level()
+}
+
+// Handle writes r to the deck.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	var log *deck.Log
+	switch Level(r.Level) {
+	case deck.DEBUG:
+		log = h.deck.DebugA(r.Message)
+	case deck.INFO:
+		log = h.deck.InfoA(r.Message)
+	case deck.WARNING:
+		log = h.deck.WarningA(r.Message)
+	default:
+		log = h.deck.ErrorA(r.Message)
+	}
+
+	attrs := h.attrs[:len(h.attrs):len(h.attrs)]
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttr(attrs, h.prefix, a)
+		return true
+	})
+	return log.With(func(s *deck.AttribStore) {
+		// A zero time is kept, rather than replaced by the time the deck captures, so
+		// backends can ignore it as slog requires.
+		deck.TimeKey.Set(s, r.Time)
+		for _, a := range attrs {
+			deck.Field(a.Key, a.Value)(s)
+		}
+	}, deck.Depth(callerDepth(r.PC))).GoErr()
+}
+
+// WithAttrs returns a Handler which adds attrs to every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = make([]deck.KeyValue, len(h.attrs), len(h.attrs)+len(attrs))
+	copy(h2.attrs, h.attrs)
+	for _, a := range attrs {
+		h2.attrs = appendAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+// WithGroup returns a Handler which qualifies the keys of all subsequent attributes with name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// appendAttr resolves a, flattens any groups it contains and appends the result to attrs.
+func appendAttr(attrs []deck.KeyValue, prefix string, a slog.Attr) []deck.KeyValue {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return append(attrs, deck.KeyValue{Key: prefix + a.Key, Value: a.Value.Any()})
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, ga := range a.Value.Group() {
+		attrs = appendAttr(attrs, prefix, ga)
+	}
+	return attrs
+}
+
+// defaultDepth is the depth of the caller of a slog.Logger output method, relative to Handle.
+const defaultDepth = 3
+
+// callerDepth returns the deck Depth of the frame identified by pc, relative to the caller of
+// callerDepth. Searching for the frame keeps the depth accurate when the Handler is wrapped by
+// other handlers or called by something other than slog.Logger.
+func callerDepth(pc uintptr) int {
+	if pc == 0 {
+		return defaultDepth
+	}
+	want, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for depth := 0; ; depth++ {
+		f, more := frames.Next()
+		if f.Function == want.Function && f.File == want.File && f.Line == want.Line {
+			return depth
+		}
+		if !more {
+			return defaultDepth
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogdeck
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/google/deck"
+	"github.com/google/deck/backends/logger"
+)
+
+// recorder is a backend which records each message as a map, in the form expected by slogtest.
+type recorder struct {
+	results []map[string]any
+}
+
+func (r *recorder) New(lvl deck.Level, msg string) deck.Composer {
+	m := map[string]any{slog.LevelKey: lvl, slog.MessageKey: msg}
+	r.results = append(r.results, m)
+	return &recording{m}
+}
+
+func (r *recorder) Close() error { return nil }
+
+type recording struct {
+	m map[string]any
+}
+
+func (r *recording) Compose(s *deck.AttribStore) error {
+	if t, ok := deck.TimeKey.Get(s); ok && !t.IsZero() {
+		r.m[slog.TimeKey] = t
+	}
+	for _, f := range deck.Fields(s) {
+		m := r.m
+		keys := strings.Split(f.Key, ".")
+		for _, g := range keys[:len(keys)-1] {
+			if _, ok := m[g]; !ok {
+				m[g] = map[string]any{}
+			}
+			m = m[g].(map[string]any)
+		}
+		m[keys[len(keys)-1]] = f.Value
+	}
+	return nil
+}
+
+func (r *recording) Write() error { return nil }
+
+func TestSlogtest(t *testing.T) {
+	d := deck.New()
+	r := &recorder{}
+	d.Add(r)
+	if err := slogtest.TestHandler(NewHandler(d), func() []map[string]any { return r.results }); err != nil {
+		t.Errorf("TestHandler(): %v", err)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		in   slog.Level
+		want deck.Level
+	}{
+		{slog.LevelDebug - 4, deck.DEBUG},
+		{slog.LevelDebug, deck.DEBUG},
+		{slog.LevelInfo, deck.INFO},
+		{slog.LevelInfo + 2, deck.INFO},
+		{slog.LevelWarn, deck.WARNING},
+		{slog.LevelError, deck.ERROR},
+		{slog.LevelError + 8, deck.ERROR},
+	}
+	for _, tt := range tests {
+		if got := Level(tt.in); got != tt.want {
+			t.Errorf("Level(%v): produced unexpected result: got %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	d := deck.New()
+	d.SetLevel(deck.WARNING)
+	l := slog.New(NewHandler(d))
+	if l.Enabled(t.Context(), slog.LevelInfo) {
+		t.Errorf("Enabled(%v): got true, want false", slog.LevelInfo)
+	}
+	if !l.Enabled(t.Context(), slog.LevelWarn) {
+		t.Errorf("Enabled(%v): got false, want true", slog.LevelWarn)
+	}
+}
+
+func TestCaller(t *testing.T) {
+	var buf bytes.Buffer
+	d := deck.New()
+	d.Add(logger.Init(&buf, log.Lshortfile))
+	l := slog.New(NewHandler(d))
+	tests := []struct {
+		desc string
+		f    func()
+	}{
+		{"Info", func() { l.Info("message") }},
+		{"With Info", func() { l.With("k", "v").Info("message") }},
+		{"LogAttrs", func() { l.LogAttrs(t.Context(), slog.LevelWarn, "message") }},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		tt.f()
+		if !strings.Contains(buf.String(), "slogdeck_test.go") {
+			t.Errorf("%s: produced unexpected caller: got %q, want slogdeck_test.go", tt.desc, buf.String())
+		}
+	}
+}
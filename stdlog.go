@@ -0,0 +1,173 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"bytes"
+	"log"
+	"regexp"
+	"sync"
+)
+
+// maxLineLength is the longest line a Writer buffers. Longer lines are split into several
+// messages.
+const maxLineLength = 64 << 10
+
+// A Writer is an io.Writer which logs each line written to it as a message. Text is buffered
+// until it ends in a newline, which is removed along with any carriage return before it.
+// Lines longer than 64 KiB are split into several messages.
+//
+// A Writer may be used by several goroutines at once. When the deck has no backends, lines are
+// discarded rather than printed with the log package, which may itself be writing to the Writer.
+type Writer struct {
+	deck  *Deck
+	level Level
+	attrs []Attrib
+
+	mu    sync.Mutex
+	buf   []byte
+	rules []levelRule
+}
+
+// A levelRule overrides the level of lines matching a regular expression.
+type levelRule struct {
+	re    *regexp.Regexp
+	level Level
+}
+
+// NewWriter returns a Writer which logs each line written to it to d at lvl, or to the default
+// deck if d is nil. The attributes are added to every message.
+//
+// Messages are attributed to the code which calls Write. When that is a wrapper, such as
+// fmt.Fprintf, the Depth attribute attributes them to the wrapper's caller instead:
+//
+//	fmt.Fprintf(deck.NewWriter(d, deck.INFO, deck.Depth(1)), "%d items\n", n)
+func NewWriter(d *Deck, lvl Level, attrs ...Attrib) *Writer {
+	if d == nil {
+		d = defaultDeck
+	}
+	return &Writer{deck: d, level: lvl, attrs: attrs}
+}
+
+// Classify makes w log lines matching re at lvl, rather than at the level w was created with.
+// Rules are tried in the order they were added, and the first match decides the level.
+//
+//	w.Classify(regexp.MustCompile(`^(?i)error:`), deck.ERROR)
+func (w *Writer) Classify(re *regexp.Regexp, lvl Level) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rules = append(w.rules, levelRule{re: re, level: lvl})
+}
+
+// Write logs each complete line in p, and buffers any text after the last newline until a
+// later Write completes it. It always reports len(p) bytes written.
+func (w *Writer) Write(p []byte) (int, error) {
+	// Lines are logged after w.mu is released, as logging may write to w again, such as when
+	// the deck's backends write through a redirected standard logger.
+	w.mu.Lock()
+	lines, rules := w.split(p), w.rules
+	w.mu.Unlock()
+	for _, line := range lines {
+		w.emit(line, rules)
+	}
+	return len(p), nil
+}
+
+// split returns the complete lines in p, and buffers any text after the last newline. w.mu
+// must be held.
+func (w *Writer) split(p []byte) []string {
+	var lines []string
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			for len(w.buf) >= maxLineLength {
+				lines = append(lines, string(w.buf[:maxLineLength]))
+				w.buf = w.buf[:copy(w.buf, w.buf[maxLineLength:])]
+			}
+			break
+		}
+		line := p[:i]
+		if len(w.buf) > 0 {
+			w.buf = append(w.buf, line...)
+			line, w.buf = w.buf, w.buf[:0]
+		}
+		for len(line) > maxLineLength {
+			lines = append(lines, string(line[:maxLineLength]))
+			line = line[maxLineLength:]
+		}
+		lines = append(lines, string(bytes.TrimSuffix(line, []byte("\r"))))
+		p = p[i+1:]
+	}
+	return lines
+}
+
+// Close logs any text written since the last newline.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	line, rules := string(w.buf), w.rules
+	w.buf = w.buf[:0]
+	w.mu.Unlock()
+	if len(line) > 0 {
+		w.emit(line, rules)
+	}
+	return nil
+}
+
+// emit logs line at the level chosen by rules. It must be called by Write or Close, which are
+// attributed the message.
+func (w *Writer) emit(line string, rules []levelRule) {
+	lvl := w.level
+	for _, r := range rules {
+		if r.re.MatchString(line) {
+			lvl = r.level
+			break
+		}
+	}
+	if !w.deck.enabled(lvl) {
+		return
+	}
+	l := newLog(w.deck.newRecord(lvl, line))
+	l.With(w.attrs...)
+	// Skip emit and Write or Close.
+	depth, _ := DepthKey.Get(&l.attributes)
+	DepthKey.Set(&l.attributes, depth+2)
+	l.Go()
+}
+
+// NewStdLogger returns a *log.Logger which logs each message to d at lvl, or to the default
+// deck if d is nil. Messages are attributed to the code which calls the *log.Logger. The
+// logger has no prefix or flags, as the deck's backends add their own.
+func NewStdLogger(d *Deck, lvl Level) *log.Logger {
+	return log.New(NewWriter(d, lvl, Depth(2)), "", 0)
+}
+
+// RedirectStdLog sends the output of the log package's standard logger, such as from
+// log.Printf, to d at lvl, or to the default deck if d is nil. It returns a function which
+// restores the previous output, prefix and flags.
+//
+// A deck whose backends write through the standard logger must not be used, as every
+// message would be logged again.
+func RedirectStdLog(d *Deck, lvl Level) (restore func()) {
+	out, prefix, flags := log.Writer(), log.Prefix(), log.Flags()
+	log.SetOutput(NewWriter(d, lvl, Depth(2)))
+	log.SetPrefix("")
+	log.SetFlags(0)
+	return func() {
+		log.SetOutput(out)
+		log.SetPrefix(prefix)
+		log.SetFlags(flags)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import "fmt"
+
+// Verbose is a guard returned by Deck.V. Its logging methods do nothing, without formatting
+// the message, when the verbosity it was created with is disabled.
+type Verbose struct {
+	deck  *Deck // nil if disabled
+	level int
+}
+
+// V returns a guard for logging INFO messages at verbosity level, in the style of glog.V.
+//
+// Whether level is enabled is decided once, when V is called, taking into account the deck's
+// minimum level, its verbosity and any SetVModule setting for the calling file. If it is
+// disabled, the guard's methods return immediately, so the arguments are never formatted and
+// no Log is allocated.
+//
+//	d.V(2).Infof("cache state: %v", cache)
+//
+// The package level V is the verbosity attribute; use Default().V for the default deck.
+func (d *Deck) V(level int) Verbose {
+	if !d.enabled(INFO) {
+		return Verbose{}
+	}
+	if level > d.root().load().verbosity && level > d.vmoduleLevel(1) {
+		return Verbose{}
+	}
+	return Verbose{deck: d, level: level}
+}
+
+// Enabled reports whether messages at the guard's verbosity are enabled.
+func (v Verbose) Enabled() bool {
+	return v.deck != nil
+}
+
+func (v Verbose) mkRecord(message string) *record {
+	l := v.deck.mkRecord(INFO, message)
+	l.checked = true
+	V(v.level)(&l.attributes)
+	return l
+}
+
+// InfoA constructs a message at the INFO level and the guard's verbosity.
+func (v Verbose) InfoA(message ...any) *Log {
+	if v.deck == nil {
+		return nopLog
+	}
+	return newLog(v.mkRecord(sprint(message...)))
+}
+
+// Info immediately logs a message with no attributes at the INFO level and the guard's
+// verbosity.
+func (v Verbose) Info(message ...any) {
+	if v.deck == nil {
+		return
+	}
+	newLog(v.mkRecord(sprint(message...))).With(Depth(1)).Go()
+}
+
+// InfofA constructs a message according to the format specifier at the INFO level and the
+// guard's verbosity.
+func (v Verbose) InfofA(format string, message ...any) *Log {
+	if v.deck == nil {
+		return nopLog
+	}
+	return newLog(v.mkRecord(fmt.Sprintf(format, message...)))
+}
+
+// Infof immediately logs a message with no attributes according to the format specifier at
+// the INFO level and the guard's verbosity.
+func (v Verbose) Infof(format string, message ...any) {
+	if v.deck == nil {
+		return
+	}
+	newLog(v.mkRecord(fmt.Sprintf(format, message...))).With(Depth(1)).Go()
+}
+
+// InfolnA constructs a message with a trailing newline at the INFO level and the guard's
+// verbosity.
+func (v Verbose) InfolnA(message ...any) *Log {
+	if v.deck == nil {
+		return nopLog
+	}
+	return newLog(v.mkRecord(fmt.Sprintln(message...)))
+}
+
+// Infoln immediately logs a message with no attributes and with a trailing newline at the
+// INFO level and the guard's verbosity.
+func (v Verbose) Infoln(message ...any) {
+	if v.deck == nil {
+		return
+	}
+	newLog(v.mkRecord(fmt.Sprintln(message...))).With(Depth(1)).Go()
+}
+
+// Enabled reports whether the default deck commits messages at lvl and verbosity v from the
+// calling code.
+func Enabled(lvl Level, v int) bool {
+	return defaultDeck.enabledAt(lvl, v)
+}
+
+// Enabled reports whether the deck commits messages at lvl and verbosity v from the calling
+// code, taking into account the deck's minimum level, its verbosity and any SetVModule
+// setting for the calling file. It can be used to skip computing expensive arguments.
+//
+//	if d.Enabled(deck.DEBUG, 2) {
+//		d.DebugA(dumpState()).With(deck.V(2)).Go()
+//	}
+//
+// Filters set on individual backends are not taken into account.
+func (d *Deck) Enabled(lvl Level, v int) bool {
+	return d.enabledAt(lvl, v)
+}
+
+// enabledAt implements Enabled, for the call site two frames above it.
+func (d *Deck) enabledAt(lvl Level, v int) bool {
+	if !d.enabled(lvl) {
+		return false
+	}
+	return v <= d.root().load().verbosity || v <= d.vmoduleLevel(2)
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deck
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SetVModule sets per-module verbosity for the default deck.
+func SetVModule(spec string) error {
+	return defaultDeck.SetVModule(spec)
+}
+
+// SetVModule raises the verbosity of the deck for individual source files or packages, in
+// the style of glog's -vmodule flag.
+//
+// The spec is a comma separated list of pattern=N settings, such as "storage/*=3,net/http=1".
+// A pattern without a slash is matched against the name of the calling file, without its
+// .go suffix, and against the name of the calling package. A pattern with slashes is matched
+// against the same number of trailing elements of the file's path and of the package's
+// import path. Patterns may use the syntax of path.Match, and the first matching pattern
+// wins.
+//
+// The caller of a message is found using its Depth attribute. A matching pattern can only
+// raise the verbosity for a call site above the verbosity set with SetVerbosity. An empty
+// spec clears the settings.
+func (d *Deck) SetVModule(spec string) error {
+	vm, err := parseVModule(spec)
+	if err != nil {
+		return err
+	}
+	d.root().vmodule.Store(vm)
+	return nil
+}
+
+// vmoduleLevel returns the verbosity set with SetVModule for the call site skip frames above
+// the caller of vmoduleLevel, or -1 if there is none.
+func (d *Deck) vmoduleLevel(skip int) int {
+	vm := d.root().vmodule.Load()
+	if vm == nil {
+		return -1
+	}
+	return vm.level(callerPC(skip + 1))
+}
+
+// vmoduleLevelAt returns the verbosity set with SetVModule for the call site pc, or -1 if
+// there is none.
+func (d *Deck) vmoduleLevelAt(pc uintptr) int {
+	vm := d.root().vmodule.Load()
+	if vm == nil {
+		return -1
+	}
+	return vm.level(pc)
+}
+
+type vfilter struct {
+	pattern string
+	parts   int // number of path elements in pattern
+	level   int
+}
+
+type vmodule struct {
+	filters []vfilter
+	cache   sync.Map // map[uintptr]int: program counter to verbosity, or -1 if unmatched
+}
+
+func parseVModule(spec string) (*vmodule, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	vm := &vmodule{}
+	for _, s := range strings.Split(spec, ",") {
+		pattern, level, ok := strings.Cut(strings.TrimSpace(s), "=")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("deck: invalid vmodule setting %q", s)
+		}
+		v, err := strconv.Atoi(level)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("deck: invalid vmodule verbosity in %q", s)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("deck: invalid vmodule pattern %q: %w", pattern, err)
+		}
+		vm.filters = append(vm.filters, vfilter{pattern: pattern, parts: strings.Count(pattern, "/") + 1, level: v})
+	}
+	return vm, nil
+}
+
+// level returns the verbosity for the call site at pc, or -1 if no pattern matches it.
+func (vm *vmodule) level(pc uintptr) int {
+	if v, ok := vm.cache.Load(pc); ok {
+		return v.(int)
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	file := strings.TrimSuffix(frame.File, ".go")
+	pkg := packagePath(frame.Function)
+	v := -1
+	for _, f := range vm.filters {
+		if f.match(file) || (pkg != "" && f.match(pkg)) {
+			v = f.level
+			break
+		}
+	}
+	vm.cache.Store(pc, v)
+	return v
+}
+
+// match reports whether the trailing path elements of name match the filter's pattern.
+func (f vfilter) match(name string) bool {
+	i := len(name)
+	for n := 0; n < f.parts && i >= 0; n++ {
+		i = strings.LastIndexByte(name[:i], '/')
+	}
+	ok, _ := path.Match(f.pattern, name[i+1:])
+	return ok
+}
+
+// packagePath returns the import path of the package containing the named function, as
+// reported by runtime.Frame.Function.
+func packagePath(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return function[:slash+1+dot]
+}